@@ -11,8 +11,10 @@ const (
 	FileExtYAML = "yaml"
 	FileExtYML  = "yml"
 
-	FormatJSON = "json"
-	FormatYAML = "yaml"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatText  = "text"
+	FormatSARIF = "sarif"
 )
 
 type CLIOutput string
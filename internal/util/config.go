@@ -0,0 +1,267 @@
+package util
+
+import (
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/deanstalker/goas/pkg/types"
+)
+
+// DefaultConfigFile is the config file goas init scaffolds
+const DefaultConfigFile = "goas.yaml"
+
+// ConfigFiles are the config files discovered at the root of a module, in the order they are looked up
+var ConfigFiles = []string{DefaultConfigFile, ".goas.json"}
+
+// Config of a goas project. Its paths are relative to the directory of the config file, LoadConfig resolves them
+// against the working directory.
+type Config struct {
+	// ModulePath is the directory of the go.mod of the module
+	ModulePath string `json:"modulePath,omitempty" yaml:"modulePath,omitempty"`
+	// MainFilePath is the file annotated with the info of the document
+	MainFilePath string `json:"mainFilePath,omitempty" yaml:"mainFilePath,omitempty"`
+	// HandlerPaths are the directories operations are parsed in
+	HandlerPaths []string `json:"handlerPaths,omitempty" yaml:"handlerPaths,omitempty"`
+	// Include are patterns of the packages operations are parsed in, and Exclude of the packages they are not, see
+	// PackagePattern
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// Outputs the document is written to
+	Outputs []Output `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+
+	TypeMappings map[string]types.TypeMapping `json:"typeMappings,omitempty" yaml:"typeMappings,omitempty"`
+
+	// Lint overrides the severity of lint rules, keyed by their id, see types.LintRules
+	Lint map[string]string `json:"lint,omitempty" yaml:"lint,omitempty"`
+}
+
+// Output the document is written to, in Format, json or yaml, or in the format of the extension of Path if it is
+// empty. Split outputs are directories the document is split into.
+type Output struct {
+	Path   string `json:"path" yaml:"path"`
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	Split  bool   `json:"split,omitempty" yaml:"split,omitempty"`
+}
+
+// LoadConfig reads a config file, json files are decoded as json and anything else as yaml
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(b, config)
+	} else {
+		err = yaml.UnmarshalStrict(b, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+	config.resolve(filepath.Dir(path))
+	return config, nil
+}
+
+// resolve joins the relative paths of the config to the directory of the config file
+func (c *Config) resolve(dir string) {
+	c.ModulePath = resolvePath(dir, c.ModulePath)
+	c.MainFilePath = resolvePath(dir, c.MainFilePath)
+	for i := range c.HandlerPaths {
+		c.HandlerPaths[i] = resolvePath(dir, c.HandlerPaths[i])
+	}
+	for i := range c.Outputs {
+		c.Outputs[i].Path = resolvePath(dir, c.Outputs[i].Path)
+	}
+	// patterns are joined to the absolute directory, as ./... cleans to the parent directory once it is joined to .
+	abs, _ := filepath.Abs(dir)
+	for _, patterns := range [][]string{c.Include, c.Exclude} {
+		for i, pattern := range patterns {
+			if strings.HasPrefix(pattern, ".") {
+				patterns[i] = resolvePath(abs, pattern)
+			}
+		}
+	}
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, filepath.FromSlash(path))
+}
+
+// SaveConfig writes a config file, json files are encoded as json and anything else as yaml. An existing file is not
+// overwritten.
+func SaveConfig(path string, config *Config) error {
+	var b []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		b, err = json.MarshalIndent(config, "", "  ")
+		b = append(b, '\n')
+	} else {
+		b, err = yaml.Marshal(config)
+	}
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(b); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ModuleRoot returns the root of the module dir is in, the closest directory with a go.mod, or an empty path if dir
+// is not in a module. The root is relative if dir is.
+func ModuleRoot(dir string) (string, error) {
+	for {
+		info, err := os.Stat(filepath.Join(dir, "go.mod"))
+		if err == nil && !info.IsDir() {
+			return dir, nil
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", err
+		}
+		if filepath.Dir(abs) == abs {
+			return "", nil
+		}
+		dir = filepath.Join(dir, "..")
+	}
+}
+
+// FindConfig returns the config file at the root of the module dir is in, see ModuleRoot and ConfigFiles, or an
+// empty path if there is none
+func FindConfig(dir string) (string, error) {
+	root, err := ModuleRoot(dir)
+	if err != nil || root == "" {
+		return "", err
+	}
+	for _, name := range ConfigFiles {
+		path := filepath.Join(root, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// ScaffoldConfig inspects the module at root for the config of a config file written to dir: the file annotated
+// with @Version is the main file, and the directories of the files annotated with @Route or @Webhook are the handler
+// paths. Existing documents named oas or openapi are the outputs, oas.json if there are none, and the lint rules are
+// listed at their default severity.
+func ScaffoldConfig(root, dir string) (*Config, error) {
+	config := &Config{Lint: map[string]string{}}
+	handlerPaths := map[string]bool{}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path == root {
+				return nil
+			}
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			// nested modules are documented on their own
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil
+		}
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				fields := strings.Fields(strings.TrimPrefix(comment.Text, "//"))
+				if len(fields) == 0 {
+					continue
+				}
+				switch strings.ToLower(fields[0]) {
+				case types.AttributeVersion:
+					if config.MainFilePath == "" {
+						config.MainFilePath = path
+					}
+				case types.AttributeRoute, types.AttributeRouter, types.AttributeWebhook:
+					handlerPaths[filepath.Dir(path)] = true
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for handlerPath := range handlerPaths {
+		config.HandlerPaths = append(config.HandlerPaths, handlerPath)
+	}
+	sort.Strings(config.HandlerPaths)
+	for _, name := range []string{"oas.json", "oas.yaml", "oas.yml", "openapi.json", "openapi.yaml", "openapi.yml"} {
+		if _, err := os.Stat(filepath.Join(root, name)); err == nil {
+			config.Outputs = append(config.Outputs, Output{Path: filepath.Join(root, name)})
+		}
+	}
+	if len(config.Outputs) == 0 {
+		config.Outputs = []Output{{Path: filepath.Join(root, "oas.json")}}
+	}
+	for _, rule := range types.LintRules {
+		config.Lint[rule.ID] = rule.Severity
+	}
+
+	if err := config.relative(root, dir); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// relative makes the paths of the config, and the module at root, relative to dir
+func (c *Config) relative(root, dir string) error {
+	dir, err := filepath.Abs(dir)
+	rel := func(path string) string {
+		if path == "" || err != nil {
+			return path
+		}
+		var relPath string
+		if path, err = filepath.Abs(path); err == nil {
+			relPath, err = filepath.Rel(dir, path)
+		}
+		return filepath.ToSlash(relPath)
+	}
+
+	if modulePath := rel(root); modulePath != "." {
+		c.ModulePath = modulePath
+	}
+	c.MainFilePath = rel(c.MainFilePath)
+	for i := range c.HandlerPaths {
+		c.HandlerPaths[i] = rel(c.HandlerPaths[i])
+	}
+	for i := range c.Outputs {
+		c.Outputs[i].Path = rel(c.Outputs[i].Path)
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/deanstalker/goas/pkg/types"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := map[string]struct {
+		fileName  string
+		content   string
+		want      *Config
+		expectErr bool
+	}{
+		"yaml type mappings": {
+			fileName: "goas.yaml",
+			content: `typeMappings:
+  github.com/google/uuid.UUID:
+    type: string
+    format: uuid
+    example: 4f6f3f5c-6b52-4a44-9d6b-1c7e1b5f4d1a
+  database/sql.NullString:
+    type: string
+    nullable: true
+`,
+			want: &Config{
+				TypeMappings: map[string]types.TypeMapping{
+					"github.com/google/uuid.UUID": {Type: "string", Format: "uuid", Example: "4f6f3f5c-6b52-4a44-9d6b-1c7e1b5f4d1a"},
+					"database/sql.NullString":     {Type: "string", Nullable: true},
+				},
+			},
+		},
+		"json type mappings": {
+			fileName: "goas.json",
+			content:  `{"typeMappings": {"example.com/money.Amount": {"type": "string", "pattern": "^[0-9]+\\.[0-9]{2}$"}}}`,
+			want: &Config{
+				TypeMappings: map[string]types.TypeMapping{
+					"example.com/money.Amount": {Type: "string", Pattern: `^[0-9]+\.[0-9]{2}$`},
+				},
+			},
+		},
+		"lint severities": {
+			fileName: "goas.yaml",
+			content: `lint:
+  operation-tags: off
+  operation-id: error
+`,
+			want: &Config{
+				Lint: map[string]string{"operation-tags": "off", "operation-id": "error"},
+			},
+		},
+		"unknown yaml key": {
+			fileName:  "goas.yaml",
+			content:   "typeMapping: {}\n",
+			expectErr: true,
+		},
+		"missing file": {
+			fileName:  "",
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "missing.yaml")
+			if tc.fileName != "" {
+				path = filepath.Join(t.TempDir(), tc.fileName)
+				assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0600))
+			}
+			got, err := LoadConfig(path)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSaveConfig(t *testing.T) {
+	config := &Config{
+		TypeMappings: map[string]types.TypeMapping{"github.com/google/uuid.UUID": {Type: "string", Format: "uuid"}},
+		Lint:         map[string]string{"operation-tags": "off", "operation-id": "error"},
+	}
+
+	for _, fileName := range []string{"goas.yaml", "goas.json"} {
+		t.Run(fileName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), fileName)
+			assert.NoError(t, SaveConfig(path, config))
+			got, err := LoadConfig(path)
+			assert.NoError(t, err)
+			assert.Equal(t, config, got)
+
+			assert.Error(t, SaveConfig(path, &Config{}))
+		})
+	}
+}
+
+func TestLoadConfigPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "goas.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`mainFilePath: cmd/api/main.go
+handlerPaths:
+  - pkg/api
+  - /srv/api/handlers
+include:
+  - ./...
+exclude:
+  - github.com/acme/api/internal/*
+  - ./pkg/api/mocks
+outputs:
+  - path: docs/oas.json
+  - path: docs/oas
+    format: yaml
+    split: true
+`), 0600))
+
+	got, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &Config{
+		MainFilePath: filepath.Join(dir, "cmd/api/main.go"),
+		HandlerPaths: []string{filepath.Join(dir, "pkg/api"), "/srv/api/handlers"},
+		Include:      []string{filepath.Join(dir, "...")},
+		Exclude:      []string{"github.com/acme/api/internal/*", filepath.Join(dir, "pkg/api/mocks")},
+		Outputs: []Output{
+			{Path: filepath.Join(dir, "docs/oas.json")},
+			{Path: filepath.Join(dir, "docs/oas"), Format: "yaml", Split: true},
+		},
+	}, got)
+}
+
+func TestFindConfig(t *testing.T) {
+	root := t.TempDir()
+	handlers := filepath.Join(root, "pkg", "api")
+	assert.NoError(t, os.MkdirAll(handlers, 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/api\n"), 0600))
+
+	path, err := FindConfig(handlers)
+	assert.NoError(t, err)
+	assert.Equal(t, "", path)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".goas.json"), []byte("{}"), 0600))
+	path, err = FindConfig(handlers)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(handlers, "..", "..", ".goas.json"), path)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "goas.yaml"), []byte("{}"), 0600))
+	path, err = FindConfig(root)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "goas.yaml"), path)
+}
+
+func TestScaffoldConfig(t *testing.T) {
+	tests := map[string]struct {
+		dir  string
+		want *Config
+	}{
+		"config at the root": {
+			dir: "../../test/integration",
+			want: &Config{
+				MainFilePath: "docs.go",
+				HandlerPaths: []string{"pkg/integration_handler"},
+				Outputs:      []Output{{Path: "oas.json"}},
+			},
+		},
+		"config in another directory": {
+			dir: "../../test",
+			want: &Config{
+				ModulePath:   "integration",
+				MainFilePath: "integration/docs.go",
+				HandlerPaths: []string{"integration/pkg/integration_handler"},
+				Outputs:      []Output{{Path: "integration/oas.json"}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ScaffoldConfig("../../test/integration", tc.dir)
+			assert.NoError(t, err)
+			assert.Len(t, got.Lint, len(types.LintRules))
+			got.Lint = nil
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
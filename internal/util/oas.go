@@ -1,6 +1,35 @@
 package util
 
-import "strings"
+import (
+	"encoding/csv"
+	"strings"
+)
+
+const (
+	SchemaNamingShort     = "short"
+	SchemaNamingQualified = "qualified"
+	SchemaNamingFullPath  = "full-path"
+)
+
+// SchemaNamings lists the supported schema naming strategies
+var SchemaNamings = []string{SchemaNamingShort, SchemaNamingQualified, SchemaNamingFullPath}
+
+const (
+	OpenAPIVersion30 = "3.0"
+	OpenAPIVersion31 = "3.1"
+	OpenAPIVersion20 = "2.0"
+)
+
+// OpenAPIVersions lists the versions of OpenAPI documents that can be generated, 2.0 being a Swagger document
+var OpenAPIVersions = []string{OpenAPIVersion30, OpenAPIVersion31, OpenAPIVersion20}
+
+const (
+	PathOrderSorted = "sorted"
+	PathOrderSource = "source"
+)
+
+// PathOrders lists the orders paths can be generated in, sorted or in the order their routes are parsed
+var PathOrders = []string{PathOrderSorted, PathOrderSource}
 
 // AddSchemaRefLinkPrefix for prefixing an object id with the oas path
 func AddSchemaRefLinkPrefix(name string) string {
@@ -16,7 +45,40 @@ func GenSchemaObjectID(typeName string) string {
 	return typeNameParts[len(typeNameParts)-1]
 }
 
+// GenSchemaObjectIDs for generating the schema object ids a type may be registered under, in order of preference.
+// short prefers the type name, qualified prefixes it with its package name and full-path with its import path,
+// the less qualified names are followed by more qualified ones to fall back to on a collision.
+func GenSchemaObjectIDs(pkgPath, typeName, naming string) []string {
+	segments := strings.Split(ReplaceBackslash(pkgPath), "/")
+	var ids []string
+	if naming == SchemaNamingShort {
+		ids = append(ids, typeName)
+	}
+	for i := len(segments) - 1; i >= 0; i-- {
+		if naming == SchemaNamingFullPath && i > 0 {
+			continue
+		}
+		qualified := append(append([]string{}, segments[i:]...), typeName)
+		ids = append(ids, strings.Join(qualified, "."))
+	}
+	return ids
+}
+
 // ReplaceBackslash with forward slash
 func ReplaceBackslash(origin string) string {
 	return strings.ReplaceAll(origin, "\\", "/")
 }
+
+// SplitEnumValues splits a comma separated list of enum values, values containing commas may be double-quoted
+func SplitEnumValues(enum string) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimSpace(enum)))
+	reader.TrimLeadingSpace = true
+	values, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values, nil
+}
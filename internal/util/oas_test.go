@@ -36,3 +36,87 @@ func TestGenSchemaObjectID(t *testing.T) {
 		})
 	}
 }
+
+func TestGenSchemaObjectIDs(t *testing.T) {
+	tests := map[string]struct {
+		pkgPath  string
+		typeName string
+		naming   string
+		want     []string
+	}{
+		"short": {
+			pkgPath:  "github.com/user/goas/billing",
+			typeName: "Error",
+			naming:   SchemaNamingShort,
+			want: []string{
+				"Error",
+				"billing.Error",
+				"goas.billing.Error",
+				"user.goas.billing.Error",
+				"github.com.user.goas.billing.Error",
+			},
+		},
+		"qualified": {
+			pkgPath:  "github.com/user/goas/billing",
+			typeName: "Error",
+			naming:   SchemaNamingQualified,
+			want: []string{
+				"billing.Error",
+				"goas.billing.Error",
+				"user.goas.billing.Error",
+				"github.com.user.goas.billing.Error",
+			},
+		},
+		"full path": {
+			pkgPath:  "github.com/user/goas/billing",
+			typeName: "Error",
+			naming:   SchemaNamingFullPath,
+			want: []string{
+				"github.com.user.goas.billing.Error",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, GenSchemaObjectIDs(tc.pkgPath, tc.typeName, tc.naming))
+		})
+	}
+}
+
+func TestSplitEnumValues(t *testing.T) {
+	tests := map[string]struct {
+		enum      string
+		want      []string
+		expectErr bool
+	}{
+		"plain values": {
+			enum: "active,pending,disabled",
+			want: []string{"active", "pending", "disabled"},
+		},
+		"spaced values": {
+			enum: " 400, 404 ,500 ",
+			want: []string{"400", "404", "500"},
+		},
+		"quoted values containing commas": {
+			enum: `"red, green",blue,"say ""hi"""`,
+			want: []string{"red, green", "blue", `say "hi"`},
+		},
+		"unterminated quote": {
+			enum:      `"red,green`,
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := SplitEnumValues(tc.enum)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package util
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PackagePattern matches packages like the patterns of the go command: by import path, or by directory for patterns
+// starting with . or /, and ... matches any string, x/... matching x itself too. Patterns are globs as well: * matches
+// any string within an element of the path, ? any character, [...] a class of characters and ** any number of
+// elements. Patterns without wildcards match import paths case insensitively.
+type PackagePattern struct {
+	pattern string
+	dir     bool
+	literal bool
+	re      *regexp.Regexp
+}
+
+// NewPackagePattern compiles a pattern, relative directories are joined to dir
+func NewPackagePattern(pattern, dir string) (PackagePattern, error) {
+	p := PackagePattern{pattern: pattern}
+	pattern = filepath.ToSlash(pattern)
+	if strings.HasPrefix(pattern, ".") || filepath.IsAbs(filepath.FromSlash(pattern)) || strings.HasPrefix(pattern, "/") {
+		p.dir = true
+		if !filepath.IsAbs(filepath.FromSlash(pattern)) {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return p, err
+			}
+			pattern = filepath.ToSlash(filepath.Join(abs, filepath.FromSlash(pattern)))
+		}
+	}
+	p.literal = !strings.ContainsAny(pattern, "*?[") && !strings.Contains(pattern, "...")
+
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return p, fmt.Errorf("%s: %w", p.pattern, err)
+	}
+	p.re = re
+	return p, nil
+}
+
+// NewPackagePatterns compiles patterns, see NewPackagePattern
+func NewPackagePatterns(patterns []string, dir string) ([]PackagePattern, error) {
+	compiled := make([]PackagePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		p, err := NewPackagePattern(strings.TrimSpace(pattern), dir)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, p)
+	}
+	return compiled, nil
+}
+
+// Match reports whether the package at importPath, in the directory pkgDir, matches the pattern
+func (p PackagePattern) Match(importPath, pkgDir string) bool {
+	if p.dir {
+		return p.re.MatchString(filepath.ToSlash(pkgDir))
+	}
+	if p.literal {
+		return strings.EqualFold(p.pattern, importPath)
+	}
+	return p.re.MatchString(importPath)
+}
+
+// String returns the pattern as it was given
+func (p PackagePattern) String() string {
+	return p.pattern
+}
+
+// compilePattern translates a pattern to a regular expression, element by element
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	elements := strings.Split(pattern, "/")
+	var b strings.Builder
+	b.WriteString("^")
+	separate := false
+	for i, element := range elements {
+		last := i == len(elements)-1
+		switch {
+		case (element == "**" || element == "...") && last && i > 0:
+			b.WriteString("(?:/.*)?")
+			continue
+		case element == "**" && last:
+			b.WriteString(".*")
+			continue
+		}
+		if separate {
+			b.WriteString("/")
+		}
+		separate = true
+		if element == "**" {
+			b.WriteString("(?:[^/]+/)*")
+			separate = false
+			continue
+		}
+		if err := compileElement(&b, element); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+func compileElement(b *strings.Builder, element string) error {
+	for i := 0; i < len(element); i++ {
+		switch {
+		case strings.HasPrefix(element[i:], "..."):
+			b.WriteString(".*")
+			i += 2
+		case element[i] == '*':
+			b.WriteString("[^/]*")
+		case element[i] == '?':
+			b.WriteString("[^/]")
+		case element[i] == '[':
+			end := strings.IndexByte(element[i+1:], ']')
+			if end < 0 {
+				return fmt.Errorf("unterminated character class")
+			}
+			class := element[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(element[i : i+1]))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package util
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackagePattern(t *testing.T) {
+	dir := t.TempDir()
+	module := "github.com/acme/api"
+
+	tests := map[string]struct {
+		pattern string
+		want    []string
+		wantErr bool
+	}{
+		"import path": {
+			pattern: "github.com/acme/API/internal/db",
+			want:    []string{"internal/db"},
+		},
+		"import path and subpackages": {
+			pattern: "github.com/acme/api/internal/...",
+			want:    []string{"internal", "internal/db", "internal/db/mocks"},
+		},
+		"wildcard element": {
+			pattern: "github.com/acme/api/*/db",
+			want:    []string{"internal/db", "pkg/db"},
+		},
+		"any number of elements": {
+			pattern: "**/mocks",
+			want:    []string{"internal/db/mocks", "mocks"},
+		},
+		"any number of elements within": {
+			pattern: "github.com/acme/api/**/db",
+			want:    []string{"internal/db", "pkg/db"},
+		},
+		"character class": {
+			pattern: "github.com/acme/api/pkg/[!d]*",
+			want:    []string{"pkg/handlers"},
+		},
+		"directory": {
+			pattern: "./pkg/db",
+			want:    []string{"pkg/db"},
+		},
+		"directory and subdirectories": {
+			pattern: "./...",
+			want:    []string{"", "internal", "internal/db", "internal/db/mocks", "mocks", "pkg/db", "pkg/handlers"},
+		},
+		"directory glob": {
+			pattern: "./**/mocks",
+			want:    []string{"internal/db/mocks", "mocks"},
+		},
+		"unterminated class": {
+			pattern: "github.com/acme/api/[a",
+			wantErr: true,
+		},
+	}
+
+	packages := []string{"", "internal", "internal/db", "internal/db/mocks", "mocks", "pkg/db", "pkg/handlers"}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pattern, err := NewPackagePattern(tc.pattern, dir)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.pattern, pattern.String())
+
+			got := []string{}
+			for _, pkg := range packages {
+				importPath := module
+				if pkg != "" {
+					importPath += "/" + pkg
+				}
+				if pattern.Match(importPath, filepath.Join(dir, filepath.FromSlash(pkg))) {
+					got = append(got, pkg)
+				}
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewPackagePatterns(t *testing.T) {
+	patterns, err := NewPackagePatterns([]string{"", " ./... ", "**/mocks"}, ".")
+	assert.NoError(t, err)
+	assert.Len(t, patterns, 2)
+
+	_, err = NewPackagePatterns([]string{"[a"}, ".")
+	assert.Error(t, err)
+}
@@ -1,43 +1,598 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/leonelquinteros/gotext"
 
 	"github.com/deanstalker/goas/internal/util"
+	"github.com/deanstalker/goas/pkg/goas"
+	"github.com/deanstalker/goas/pkg/types"
 
 	"github.com/urfave/cli"
 )
 
 var version = "v1.0.0"
 
-func action(c *cli.Context) error {
-	p, err := newParser(
-		util.ModulePath(c.GlobalString("module-path")),
-		c.GlobalString("main-file-path"),
-		c.GlobalString("handler-path"),
-		c.GlobalString("exclude-packages"),
-		c.GlobalBool("debug"),
-	)
+// generateAction generates the document, and writes it to --output, the outputs of the config file or stdout. It is
+// the action of generate, and of goas invoked without a command.
+func generateAction(c *cli.Context) error {
+	output := util.CLIOutput(stringFlag(c, "output"))
+	format := stringFlag(c, "format")
+
+	outputFormat := output.GetFormat()
+	if format != "" {
+		outputFormat = strings.ToLower(format)
+	}
+
+	if bundle := stringFlag(c, "bundle"); bundle != "" {
+		document, err := types.BundleDocument(bundle)
+		if err != nil {
+			return fmt.Errorf(gotext.Get("error.bundle.failed", bundle, err))
+		}
+		_, err = goas.WriteDocument(document, stringFlag(c, "output"), output.GetMode(), outputFormat)
+		return err
+	}
+
+	options, config, err := optionsFromContext(c)
 	if err != nil {
 		return err
 	}
+	if boolFlag(c, "list-packages") {
+		return listPackages(options)
+	}
 
-	output := util.CLIOutput(c.GlobalString("output"))
-	format := c.GlobalString("format")
+	var targets []target
+	if stringFlag(c, "output") != "" || len(config.Outputs) == 0 {
+		mode := output.GetMode()
+		if boolFlag(c, "split") {
+			if stringFlag(c, "output") == "" {
+				return fmt.Errorf(gotext.Get("error.split.missing-output"))
+			}
+			mode = goas.ModeDirWriter
+		}
+		if boolFlag(c, "check") {
+			if stringFlag(c, "output") == "" {
+				return fmt.Errorf(gotext.Get("error.check.missing-output"))
+			}
+			mode = goas.ModeCheck
+			if boolFlag(c, "split") {
+				mode = goas.ModeDirCheck
+			}
+		}
+		targets = append(targets, target{path: stringFlag(c, "output"), mode: mode, format: outputFormat})
+	} else {
+		for _, output := range config.Outputs {
+			target := target{path: output.Path, mode: goas.ModeFileWriter, format: strings.ToLower(output.Format)}
+			if target.format == "" {
+				target.format = util.CLIOutput(output.Path).GetFormat()
+			}
+			if !util.IsInStringList([]string{goas.FormatJSON, goas.FormatYAML}, target.format) {
+				return fmt.Errorf(gotext.Get("error.parser.invalid-format", output.Format))
+			}
+			switch {
+			case output.Split && boolFlag(c, "check"):
+				target.mode = goas.ModeDirCheck
+			case output.Split:
+				target.mode = goas.ModeDirWriter
+			case boolFlag(c, "check"):
+				target.mode = goas.ModeCheck
+			}
+			targets = append(targets, target)
+		}
+	}
 
-	outputFormat := output.GetFormat()
-	if format != "" {
-		outputFormat = strings.ToLower(format)
+	g, err := goas.New(options)
+	if err != nil {
+		return err
+	}
+	_, err = g.Build(context.Background())
+	if err == nil {
+		var document interface{}
+		document, err = g.Document()
+		for _, target := range targets {
+			if err != nil {
+				break
+			}
+			_, err = goas.WriteDocument(document, target.path, target.mode, target.format)
+		}
 	}
-	_, err = p.CreateOAS(c.GlobalString("output"), output.GetMode(), outputFormat)
+	printDiagnostics(g.Diagnostics())
 	return err
 }
 
+// listPackages prints the packages of the module operations are parsed in, and the packages only parsed for types
+func listPackages(options goas.Options) error {
+	g, err := goas.New(options)
+	if err != nil {
+		return err
+	}
+	operations, typesOnly, err := g.Packages(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, list := range []struct {
+		msgid    string
+		packages []goas.Package
+	}{
+		{"list-packages.operations", operations},
+		{"list-packages.types-only", typesOnly},
+	} {
+		fmt.Println(gotext.Get(list.msgid, len(list.packages)))
+		for _, known := range list.packages {
+			fmt.Printf("  %s\n", known.ImportPath)
+		}
+	}
+	return nil
+}
+
+// target the document is written to, see goas.WriteDocument
+type target struct {
+	path   string
+	mode   string
+	format string
+}
+
+// optionsFromContext returns the options configured by the config file and the flags, which take precedence, and the
+// config file. The config file is --config, or the config file at the root of the module, see util.FindConfig.
+func optionsFromContext(c *cli.Context) (goas.Options, *util.Config, error) {
+	config, err := configFromContext(c)
+	if err != nil {
+		return goas.Options{}, nil, err
+	}
+
+	options := goas.Options{
+		ModulePath:        config.ModulePath,
+		MainFilePath:      config.MainFilePath,
+		HandlerPaths:      config.HandlerPaths,
+		IncludePackages:   config.Include,
+		ExcludePackages:   config.Exclude,
+		TypeMappings:      config.TypeMappings,
+		SchemaNaming:      stringFlag(c, "schema-naming"),
+		EmbeddedAllOf:     boolFlag(c, "embedded-allof"),
+		Nullable:          boolFlag(c, "nullable"),
+		OpenAPIVersion:    stringFlag(c, "openapi-version"),
+		PathOrder:         stringFlag(c, "path-order"),
+		DeclarePathParams: boolFlag(c, "declare-path-params"),
+		Strict:            boolFlag(c, "strict"),
+		AllErrors:         boolFlag(c, "all-errors"),
+		BestEffort:        boolFlag(c, "best-effort"),
+		Debug:             boolFlag(c, "debug"),
+	}
+	if modulePath := stringFlag(c, "module-path"); modulePath != "" {
+		options.ModulePath = modulePath
+	}
+	if mainFilePath := stringFlag(c, "main-file-path"); mainFilePath != "" {
+		options.MainFilePath = mainFilePath
+	}
+	if handlerPaths := stringSliceFlag(c, "handler-path"); len(handlerPaths) > 0 {
+		options.HandlerPaths = handlerPaths
+	}
+	if include := stringSliceFlag(c, "include"); len(include) > 0 {
+		options.IncludePackages = include
+	}
+	exclude := stringSliceFlag(c, "exclude")
+	if excludePackages := stringFlag(c, "exclude-packages"); excludePackages != "" {
+		exclude = append(exclude, strings.Split(excludePackages, ",")...)
+	}
+	if len(exclude) > 0 {
+		options.ExcludePackages = exclude
+	}
+
+	return options, config, nil
+}
+
+// configFromContext loads the --config file, or the config file at the root of the module, which is then the module
+// path unless the file sets one. The config is empty if there is no config file.
+func configFromContext(c *cli.Context) (*util.Config, error) {
+	path := stringFlag(c, "config")
+	discovered := path == ""
+	if discovered {
+		dir := stringFlag(c, "module-path")
+		if dir == "" {
+			dir = "."
+		}
+		var err error
+		if path, err = util.FindConfig(dir); err != nil || path == "" {
+			return &util.Config{}, err
+		}
+	}
+
+	config, err := util.LoadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf(gotext.Get("error.config.load-failed", path, err))
+	}
+	if discovered && config.ModulePath == "" {
+		config.ModulePath = filepath.Dir(path)
+	}
+	return config, nil
+}
+
+// stringFlag returns the value of a flag of the command, or of the global flag if the command does not set it
+func stringFlag(c *cli.Context, name string) string {
+	if c.IsSet(name) {
+		return c.String(name)
+	}
+	return c.GlobalString(name)
+}
+
+// boolFlag returns the value of a flag of the command, or of the global flag if the command does not set it
+func boolFlag(c *cli.Context, name string) bool {
+	if c.IsSet(name) {
+		return c.Bool(name)
+	}
+	return c.GlobalBool(name)
+}
+
+// stringSliceFlag returns the values of a repeatable flag of the command, or of the global flag if the command does not
+// set it
+func stringSliceFlag(c *cli.Context, name string) []string {
+	if c.IsSet(name) {
+		return c.StringSlice(name)
+	}
+	return c.GlobalStringSlice(name)
+}
+
+// printDiagnostics logs diagnostics, in the order they were reported
+func printDiagnostics(diagnostics []types.Diagnostic) {
+	for _, diagnostic := range diagnostics {
+		log.Println(diagnostic.String())
+	}
+}
+
+// diffAction reports the changes between two documents, and fails if any of them is breaking
+func diffAction(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return fmt.Errorf(gotext.Get("error.diff.arguments"))
+	}
+	oldDocument, err := types.ReadOpenAPIDocument(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	newDocument, err := types.ReadOpenAPIDocument(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	changes := types.DiffOpenAPI(oldDocument, newDocument)
+	breaking := 0
+	for _, change := range changes {
+		if change.Breaking {
+			breaking++
+		}
+	}
+
+	switch format := c.String("format"); format {
+	case util.FormatText:
+		for _, change := range changes {
+			fmt.Println(change)
+		}
+	case util.FormatJSON:
+		report := struct {
+			Breaking int            `json:"breaking"`
+			Changes  []types.Change `json:"changes"`
+		}{
+			Breaking: breaking,
+			Changes:  append([]types.Change{}, changes...),
+		}
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+	default:
+		return fmt.Errorf(gotext.Get("error.diff.invalid-format", format))
+	}
+
+	if breaking > 0 {
+		return fmt.Errorf(gotext.Get("error.diff.breaking", breaking))
+	}
+	return nil
+}
+
+// lintAction lints the document that is generated, and fails if any rule reports an error
+func lintAction(c *cli.Context) error {
+	format := c.String("format")
+	if !util.IsInStringList([]string{util.FormatText, util.FormatJSON, util.FormatSARIF}, format) {
+		return fmt.Errorf(gotext.Get("error.lint.invalid-format", format))
+	}
+
+	options, config, err := optionsFromContext(c)
+	if err != nil {
+		return err
+	}
+	severities := config.Lint
+	for _, rule := range c.StringSlice("rule") {
+		id, severity, ok := strings.Cut(rule, "=")
+		if !ok {
+			return fmt.Errorf(gotext.Get("error.lint.invalid-rule", rule))
+		}
+		if severities == nil {
+			severities = map[string]string{}
+		}
+		severities[id] = severity
+	}
+
+	g, err := goas.New(options)
+	if err != nil {
+		return err
+	}
+	_, err = g.Build(context.Background())
+	printDiagnostics(g.Diagnostics())
+	if err != nil {
+		return err
+	}
+	results, err := g.Lint(severities)
+	if err != nil {
+		return err
+	}
+	errors := 0
+	for _, result := range results {
+		if result.Severity == types.SeverityError {
+			errors++
+		}
+	}
+
+	var report interface{}
+	switch format {
+	case util.FormatText:
+		for _, result := range results {
+			fmt.Printf("%s: %s\n", g.Source(result.Pointer), result)
+		}
+	case util.FormatJSON:
+		report = struct {
+			Errors  int                `json:"errors"`
+			Results []types.LintResult `json:"results"`
+		}{
+			Errors:  errors,
+			Results: append([]types.LintResult{}, results...),
+		}
+	case util.FormatSARIF:
+		report = types.LintSARIF(results, g.Source)
+	}
+	if report != nil {
+		output, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+	}
+
+	if errors > 0 {
+		return fmt.Errorf(gotext.Get("error.lint.errors", errors))
+	}
+	return nil
+}
+
+// validateAction validates a document against the OpenAPI specification, and fails if it violates it
+func validateAction(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf(gotext.Get("error.validate.arguments"))
+	}
+	file := c.Args().First()
+	document, err := types.ReadDocument(file)
+	if err != nil {
+		return err
+	}
+	violations, err := types.ValidateDocument(document)
+	if err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		fmt.Printf("%s: %s\n", file, violation)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf(gotext.Get("error.validate.violations", len(violations)))
+	}
+	return nil
+}
+
+// serveAction serves the document and a Swagger UI rendering it. The document is generated again for every request,
+// changes to the annotations show once the page is reloaded.
+func serveAction(c *cli.Context) error {
+	options, _, err := optionsFromContext(c)
+	if err != nil {
+		return err
+	}
+	if _, err := goas.New(options); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.json", documentHandler(options, goas.FormatJSON))
+	mux.HandleFunc("/openapi.yaml", documentHandler(options, goas.FormatYAML))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(swaggerUI))
+	})
+
+	addr := c.String("addr")
+	log.Println(gotext.Get("serve.listening", addr))
+	return http.ListenAndServe(addr, mux)
+}
+
+// documentHandler generates the document in format, and responds with it
+func documentHandler(options goas.Options, format string) http.HandlerFunc {
+	options.Format = format
+	contentType := "application/json"
+	if format == goas.FormatYAML {
+		contentType = "application/yaml"
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		g, err := goas.New(options)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var document *string
+		_, err = g.Build(r.Context())
+		if err == nil {
+			document, err = g.Write("", goas.ModeTest)
+		}
+		printDiagnostics(g.Diagnostics())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(*document))
+	}
+}
+
+// swaggerUI renders /openapi.json, with Swagger UI loaded from unpkg
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>goas</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// initAction scaffolds a config file by inspecting the module, at its root unless another file is given
+func initAction(c *cli.Context) error {
+	dir := stringFlag(c, "module-path")
+	if dir == "" {
+		dir = "."
+	}
+	root, err := util.ModuleRoot(dir)
+	if err != nil {
+		return err
+	}
+	if root == "" {
+		return fmt.Errorf(gotext.Get("error.init.no-module", dir))
+	}
+
+	// the global --config is the file read by the other commands, not the one scaffolded
+	path := c.String("config")
+	if path == "" && c.NArg() > 0 {
+		path = c.Args().First()
+	}
+	if path == "" {
+		path = filepath.Join(root, util.DefaultConfigFile)
+	}
+	config, err := util.ScaffoldConfig(root, filepath.Dir(path))
+	if err == nil {
+		err = util.SaveConfig(path, config)
+	}
+	if err != nil {
+		return fmt.Errorf(gotext.Get("error.init.failed", path, err))
+	}
+	log.Println(gotext.Get("init.created", path))
+	return nil
+}
+
+func getCommands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:   "generate",
+			Usage:  gotext.Get("usage.generate"),
+			Flags:  getFlags(),
+			Action: generateAction,
+		},
+		{
+			Name:      "validate",
+			Usage:     gotext.Get("usage.validate"),
+			ArgsUsage: "file",
+			Action:    validateAction,
+		},
+		{
+			Name:  "lint",
+			Usage: gotext.Get("usage.lint"),
+			Flags: append(getParseFlags(),
+				cli.StringFlag{
+					Name:  "format",
+					Value: util.FormatText,
+					Usage: gotext.Get("usage.lint-format"),
+				},
+				cli.StringSliceFlag{
+					Name:  "rule",
+					Usage: gotext.Get("usage.lint-rule"),
+				},
+			),
+			Action: lintAction,
+		},
+		{
+			Name:      "diff",
+			Usage:     gotext.Get("usage.diff"),
+			ArgsUsage: "old new",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "format",
+					Value: util.FormatText,
+					Usage: gotext.Get("usage.diff-format"),
+				},
+			},
+			Action: diffAction,
+		},
+		{
+			Name:  "serve",
+			Usage: gotext.Get("usage.serve"),
+			Flags: append(getParseFlags(),
+				cli.StringFlag{
+					Name:  "addr",
+					Value: "localhost:8080",
+					Usage: gotext.Get("usage.serve-addr"),
+				},
+			),
+			Action: serveAction,
+		},
+		{
+			Name:      "init",
+			Usage:     gotext.Get("usage.init"),
+			ArgsUsage: "[file]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "module-path",
+					Value: "",
+					Usage: gotext.Get("usage.module-path"),
+				},
+				cli.StringFlag{
+					Name:  "config",
+					Value: "",
+					Usage: gotext.Get("usage.init-config"),
+				},
+			},
+			Action: initAction,
+		},
+		{
+			Name:  "version",
+			Usage: gotext.Get("usage.version"),
+			Action: func(c *cli.Context) error {
+				cli.ShowVersion(c)
+				return nil
+			},
+		},
+	}
+}
+
+// getFlags returns the flags generating a document, they are global flags and flags of generate
 func getFlags() []cli.Flag {
+	return append(getParseFlags(), getOutputFlags()...)
+}
+
+// getParseFlags returns the flags parsing the module into a document
+func getParseFlags() []cli.Flag {
 	return []cli.Flag{
 		cli.StringFlag{
 			Name:  "module-path",
@@ -49,11 +604,77 @@ func getFlags() []cli.Flag {
 			Value: "",
 			Usage: gotext.Get("usage.main-file-path"),
 		},
-		cli.StringFlag{
+		cli.StringSliceFlag{
 			Name:  "handler-path",
-			Value: "",
 			Usage: gotext.Get("usage.handler-path"),
 		},
+		cli.StringSliceFlag{
+			Name:  "include",
+			Usage: gotext.Get("usage.include"),
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: gotext.Get("usage.exclude"),
+		},
+		cli.StringFlag{
+			Name:  "exclude-packages",
+			Value: "",
+			Usage: gotext.Get("usage.exclude-packages"),
+		},
+		cli.StringFlag{
+			Name:  "config",
+			Value: "",
+			Usage: gotext.Get("usage.config"),
+		},
+		cli.StringFlag{
+			Name:  "schema-naming",
+			Value: util.SchemaNamingShort,
+			Usage: gotext.Get("usage.schema-naming"),
+		},
+		cli.BoolFlag{
+			Name:  "embedded-allof",
+			Usage: gotext.Get("usage.embedded-allof"),
+		},
+		cli.BoolFlag{
+			Name:  "nullable",
+			Usage: gotext.Get("usage.nullable"),
+		},
+		cli.StringFlag{
+			Name:  "openapi-version",
+			Value: util.OpenAPIVersion30,
+			Usage: gotext.Get("usage.openapi-version"),
+		},
+		cli.StringFlag{
+			Name:  "path-order",
+			Value: util.PathOrderSorted,
+			Usage: gotext.Get("usage.path-order"),
+		},
+		cli.BoolFlag{
+			Name:  "declare-path-params",
+			Usage: gotext.Get("usage.declare-path-params"),
+		},
+		cli.BoolFlag{
+			Name:  "strict",
+			Usage: gotext.Get("usage.strict"),
+		},
+		cli.BoolFlag{
+			Name:  "all-errors",
+			Usage: gotext.Get("usage.all-errors"),
+		},
+		cli.BoolFlag{
+			Name:  "best-effort",
+			Usage: gotext.Get("usage.best-effort"),
+		},
+		cli.BoolFlag{
+			Name:  "debug",
+			Usage: gotext.Get("usage.debug"),
+		},
+	}
+}
+
+// getOutputFlags returns the flags writing a document
+func getOutputFlags() []cli.Flag {
+	return []cli.Flag{
 		cli.StringFlag{
 			Name:  "output",
 			Value: "",
@@ -64,14 +685,22 @@ func getFlags() []cli.Flag {
 			Value: "json",
 			Usage: gotext.Get("usage.format"),
 		},
-		cli.StringFlag{
-			Name:  "exclude-packages",
-			Value: "",
-			Usage: gotext.Get("usage.exclude-packages"),
+		cli.BoolFlag{
+			Name:  "split",
+			Usage: gotext.Get("usage.split"),
 		},
 		cli.BoolFlag{
-			Name:  "debug",
-			Usage: gotext.Get("usage.debug"),
+			Name:  "check",
+			Usage: gotext.Get("usage.check"),
+		},
+		cli.BoolFlag{
+			Name:  "list-packages",
+			Usage: gotext.Get("usage.list-packages"),
+		},
+		cli.StringFlag{
+			Name:  "bundle",
+			Value: "",
+			Usage: gotext.Get("usage.bundle"),
 		},
 	}
 }
@@ -93,7 +722,8 @@ func main() {
 		return nil
 	}
 	app.Flags = getFlags()
-	app.Action = action
+	app.Commands = getCommands()
+	app.Action = generateAction
 
 	err := app.Run(os.Args)
 	if err != nil {
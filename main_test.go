@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+
+	"github.com/deanstalker/goas/internal/util"
+)
+
+func TestMain(m *testing.M) {
+	gotext.Configure("./locales", "en", "default")
+	os.Exit(m.Run())
+}
+
+func newApp() *cli.App {
+	app := cli.NewApp()
+	app.Flags = getFlags()
+	app.Commands = getCommands()
+	app.Action = generateAction
+	return app
+}
+
+func TestInit(t *testing.T) {
+	writeModule := func(t *testing.T) string {
+		root := t.TempDir()
+		files := map[string]string{
+			"go.mod":              "module example.com/api\n\ngo 1.22\n",
+			"main.go":             "// @Version 1.0.0\n// @Title API\npackage main\n\nfunc main() {}\n",
+			"handlers/handler.go": "package handlers\n\n// @Route /pets [get]\nfunc List() {}\n",
+		}
+		for name, content := range files {
+			path := filepath.Join(root, filepath.FromSlash(name))
+			assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		}
+		return root
+	}
+
+	tests := map[string]struct {
+		args func(root string) []string
+		// config is the path of the scaffolded config relative to root, and modulePath the module path it sets, empty if
+		// the config is at the root of the module
+		config     string
+		modulePath string
+	}{
+		"module path": {
+			args: func(root string) []string {
+				return []string{"goas", "init", "--module-path", root}
+			},
+			config: "goas.yaml",
+		},
+		"module path and config": {
+			args: func(root string) []string {
+				return []string{"goas", "init", "--module-path", root, "--config", filepath.Join(root, "docs", "goas.json")}
+			},
+			config:     "docs/goas.json",
+			modulePath: ".",
+		},
+		"global module path and file": {
+			args: func(root string) []string {
+				return []string{"goas", "--module-path", root, "init", filepath.Join(root, ".goas.json")}
+			},
+			config: ".goas.json",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := writeModule(t)
+			assert.NoError(t, os.MkdirAll(filepath.Join(root, "docs"), 0o755))
+			path := filepath.Join(root, filepath.FromSlash(test.config))
+
+			err := newApp().Run(test.args(root))
+			if !assert.NoError(t, err) {
+				return
+			}
+			config, err := util.LoadConfig(path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, filepath.Join(root, "main.go"), config.MainFilePath)
+			assert.Equal(t, []string{filepath.Join(root, "handlers")}, config.HandlerPaths)
+			modulePath := ""
+			if test.modulePath != "" {
+				modulePath = filepath.Join(root, test.modulePath)
+			}
+			assert.Equal(t, modulePath, config.ModulePath)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// Package goas generates OpenAPI documents from the annotations of the handler funcs of a go module, and the types
+// they reference. Messages are translated with gotext, configure it with the locales of goas to render them.
+package goas
+
+import (
+	"context"
+	"go/token"
+	"strings"
+
+	"github.com/deanstalker/goas/internal/util"
+	"github.com/deanstalker/goas/pkg/types"
+)
+
+// Options of the generation of a document. The zero value generates the OpenAPI 3.0 document of the module in the
+// working directory, as json.
+type Options struct {
+	// ModulePath is the directory of the go.mod of the module, the working directory if empty
+	ModulePath string
+	// MainFilePath is the file annotated with the info of the document, the main file of the module if empty
+	MainFilePath string
+	// HandlerPaths are the directories operations are parsed in, with their subdirectories
+	HandlerPaths []string
+	// IncludePackages are patterns of the packages operations are parsed in, as well as HandlerPaths, the whole module
+	// if both are empty. Patterns match import paths, or directories relative to the working directory for patterns
+	// starting with ., see util.PackagePattern.
+	IncludePackages []string
+	// ExcludePackages are patterns of the packages operations are not parsed in, even if they are included. Packages
+	// operations are not parsed in are still parsed for the types they declare.
+	ExcludePackages []string
+
+	// Format the document is written in, json (default) or yaml
+	Format string
+	// TypeMappings override the schema of types, keyed by their import path and name
+	TypeMappings map[string]types.TypeMapping
+	// SchemaNaming is the strategy component schemas are named with, short (default), qualified or full-path
+	SchemaNaming string
+	// EmbeddedAllOf composes structs from the schemas of their embedded structs with allOf
+	EmbeddedAllOf bool
+	// Nullable documents pointer, slice and map fields as nullable
+	Nullable bool
+	// OpenAPIVersion of the document, 3.0 (default), 3.1 or 2.0 for swagger
+	OpenAPIVersion string
+	// PathOrder of the document, sorted (default) or source
+	PathOrder string
+
+	// DeclarePathParams declares the parameters of route templates that are not declared with @Param as required
+	// strings, instead of failing
+	DeclarePathParams bool
+	// Strict fails on violations of the OpenAPI specification, instead of reporting them as warnings
+	Strict bool
+	// AllErrors reports the errors of every annotation that fails to parse, instead of failing at the first
+	AllErrors bool
+	// BestEffort documents the types whose declaration cannot be found as empty schemas, instead of failing
+	BestEffort bool
+
+	Debug bool
+}
+
+// Generator builds the document of a module, and writes it
+type Generator struct {
+	options Options
+	parser  *parser
+}
+
+// New checks the options, and returns a generator of the document they describe
+func New(options Options) (*Generator, error) {
+	p, err := newParser(
+		util.ModulePath(options.ModulePath),
+		options.MainFilePath,
+		"",
+		"",
+		options.Debug,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, handlerPath := range options.HandlerPaths {
+		if err := p.addHandlerPath(handlerPath); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.addPatterns(&p.Include, options.IncludePackages); err != nil {
+		return nil, err
+	}
+	if err := p.addPatterns(&p.Exclude, options.ExcludePackages); err != nil {
+		return nil, err
+	}
+
+	if options.Format == "" {
+		options.Format = FormatJSON
+	}
+	if !util.IsInStringList([]string{FormatJSON, FormatYAML}, options.Format) {
+		return nil, p.Errorf("error.parser.invalid-format", options.Format)
+	}
+	if options.SchemaNaming != "" {
+		if !util.IsInStringList(util.SchemaNamings, options.SchemaNaming) {
+			return nil, p.Errorf("error.parser.invalid-schema-naming", options.SchemaNaming, strings.Join(util.SchemaNamings, ", "))
+		}
+		p.SchemaNaming = options.SchemaNaming
+	}
+	if options.OpenAPIVersion != "" {
+		if !util.IsInStringList(util.OpenAPIVersions, options.OpenAPIVersion) {
+			return nil, p.Errorf("error.parser.invalid-openapi-version", options.OpenAPIVersion, strings.Join(util.OpenAPIVersions, ", "))
+		}
+		p.OpenAPIVersion = options.OpenAPIVersion
+	}
+	if options.PathOrder != "" {
+		if !util.IsInStringList(util.PathOrders, options.PathOrder) {
+			return nil, p.Errorf("error.parser.invalid-path-order", options.PathOrder, strings.Join(util.PathOrders, ", "))
+		}
+		p.PathOrder = options.PathOrder
+	}
+	p.AddTypeMappings(options.TypeMappings)
+	p.EmbeddedAllOf = options.EmbeddedAllOf
+	p.Nullable = options.Nullable
+	p.DeclarePathParams = options.DeclarePathParams
+	p.Strict = options.Strict
+	p.AllErrors = options.AllErrors
+	p.BestEffort = options.BestEffort
+
+	return &Generator{options: options, parser: p}, nil
+}
+
+// Generate builds the document of a module, see Generator.Build
+func Generate(ctx context.Context, options Options) (*types.OpenAPIObject, []types.Diagnostic, error) {
+	g, err := New(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	document, err := g.Build(ctx)
+	return document, g.Diagnostics(), err
+}
+
+// Build parses the module into its OpenAPI 3.0 document, and validates it. Documents generated as OpenAPI 3.1 are
+// converted to 3.1, swagger documents are converted from the 3.0 document once they are written.
+func (g *Generator) Build(ctx context.Context) (*types.OpenAPIObject, error) {
+	if err := g.parser.build(ctx); err != nil {
+		return nil, err
+	}
+	if g.parser.OpenAPIVersion == util.OpenAPIVersion31 {
+		types.ConvertToOpenAPI31(&g.parser.OpenAPI)
+	}
+	return &g.parser.OpenAPI, nil
+}
+
+// Package of the module, by its import path and directory
+type Package struct {
+	ImportPath string
+	Dir        string
+}
+
+// Packages lists the packages of the module operations are parsed in, and the packages that are only parsed for the
+// types they declare, sorted by their directory. The module is loaded without building the document.
+func (g *Generator) Packages(ctx context.Context) (operations, typesOnly []Package, err error) {
+	operationPkgs, typesOnlyPkgs, err := g.parser.listPackages(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, known := range operationPkgs {
+		operations = append(operations, Package{ImportPath: known.Name, Dir: known.Path})
+	}
+	for _, known := range typesOnlyPkgs {
+		typesOnly = append(typesOnly, Package{ImportPath: known.Name, Dir: known.Path})
+	}
+	return operations, typesOnly, nil
+}
+
+// Document returns the document that is built as it is written, in the version and path order of the options
+func (g *Generator) Document() (interface{}, error) {
+	return g.parser.convertDocument()
+}
+
+// Write writes the document that is built in the version, path order and format of the options, see WriteDocument
+func (g *Generator) Write(path, mode string) (*string, error) {
+	document, err := g.Document()
+	if err != nil {
+		return nil, err
+	}
+	return WriteDocument(document, path, mode, g.options.Format)
+}
+
+// Lint the document that is built with the lint rules, at the severities rules are configured with by their id, see
+// types.LintRules
+func (g *Generator) Lint(severities map[string]string) ([]types.LintResult, error) {
+	results, err := types.Lint(&g.parser.OpenAPI, severities)
+	if err != nil {
+		return nil, g.parser.Errorf("error.lint.invalid-config", err)
+	}
+	return results, nil
+}
+
+// Diagnostics reported while the document was built and written, in the order they were reported
+func (g *Generator) Diagnostics() []types.Diagnostic {
+	return g.parser.Diagnostics
+}
+
+// Source returns the position of the annotation or declaration a json pointer of the document was generated from,
+// relative to the module
+func (g *Generator) Source(pointer string) token.Position {
+	return g.parser.sourcePosition(pointer)
+}
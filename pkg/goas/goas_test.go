@@ -0,0 +1,282 @@
+package goas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/leonelquinteros/gotext"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/deanstalker/goas/internal/util"
+	"github.com/deanstalker/goas/pkg/types"
+)
+
+// TestMain runs the tests from the root of the module, which the fixtures and locales are relative to
+func TestMain(m *testing.M) {
+	if err := os.Chdir("../.."); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	gotext.Configure("./locales", "en", "default")
+	os.Exit(m.Run())
+}
+
+// integrationOptions generate the petstore document of the integration fixture
+func integrationOptions() Options {
+	modulePath := util.ModulePath("./")
+	path, _ := modulePath.Get()
+	return Options{
+		ModulePath:      "./",
+		MainFilePath:    "test/integration/docs.go",
+		HandlerPaths:    []string{"test/integration/pkg/integration_handler"},
+		ExcludePackages: []string{fmt.Sprintf("%s/test/unit", path)},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := map[string]struct {
+		options         func(options Options) Options
+		wantOpenAPI     string
+		wantPaths       []string
+		wantDiagnostics int
+		wantErr         bool
+	}{
+		"openapi 3.0": {
+			options:     func(options Options) Options { return options },
+			wantOpenAPI: types.OpenAPIVersion,
+			wantPaths:   []string{"/pets"},
+		},
+		"openapi 3.1": {
+			options: func(options Options) Options {
+				options.OpenAPIVersion = util.OpenAPIVersion31
+				return options
+			},
+			wantOpenAPI: types.OpenAPIVersion31,
+			wantPaths:   []string{"/pets"},
+		},
+		"excluded glob": {
+			options: func(options Options) Options {
+				path, _ := util.ModulePath("./").Get()
+				options.ExcludePackages = []string{path + "/test/*", path + "/test/integration/pkg/*"}
+				return options
+			},
+			wantOpenAPI: types.OpenAPIVersion,
+			wantPaths:   []string{},
+		},
+		"unknown handler path": {
+			options: func(options Options) Options {
+				options.HandlerPaths = append(options.HandlerPaths, "test/integration/pkg/missing")
+				return options
+			},
+			wantErr: true,
+		},
+		"unknown schema naming": {
+			options: func(options Options) Options {
+				options.SchemaNaming = "long"
+				return options
+			},
+			wantErr: true,
+		},
+		"unknown format": {
+			options: func(options Options) Options {
+				options.Format = "toml"
+				return options
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			document, diagnostics, err := Generate(context.Background(), tc.options(integrationOptions()))
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, diagnostics, tc.wantDiagnostics)
+			if assert.NotNil(t, document) {
+				assert.Equal(t, tc.wantOpenAPI, document.OpenAPI)
+				assert.Equal(t, "Swagger Pet Store", document.Info.Title)
+				paths := []string{}
+				for path := range document.Paths {
+					paths = append(paths, path)
+				}
+				assert.ElementsMatch(t, tc.wantPaths, paths)
+			}
+		})
+	}
+}
+
+func TestIntegration(t *testing.T) {
+	// @see https://github.com/OAI/OpenAPI-Specification/blob/master/examples/v3.0/petstore.yaml
+	tests := map[string]struct {
+		mode   string
+		format string
+	}{
+		"integration test - yaml": {
+			ModeTest,
+			FormatYAML,
+		},
+		"integration test - json": {
+			ModeTest,
+			FormatJSON,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			options := integrationOptions()
+			options.Format = tc.format
+			g, err := New(options)
+			assert.NoError(t, err)
+			_, err = g.Build(context.Background())
+			assert.NoError(t, err)
+			test, err := g.Write("", tc.mode)
+			assert.NoError(t, err)
+
+			assert.NotEmpty(t, test)
+
+			var oapi *types.OpenAPIObject
+			switch tc.format {
+			case FormatYAML:
+				err = yaml.Unmarshal([]byte(*test), &oapi)
+			case FormatJSON:
+				err = json.Unmarshal([]byte(*test), &oapi)
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, "3.0.0", oapi.OpenAPI)
+			assert.Equal(t, "Swagger Pet Store", oapi.Info.Title)
+			assert.Equal(t, "MIT", oapi.Info.License.Name)
+			assert.Equal(t, "http://petstore.swagger.io/v1", oapi.Servers[0].URL)
+			assert.Equal(t, "List all pets", oapi.Paths["/pets"].Get.Summary)
+			assert.Equal(t, "listPets", oapi.Paths["/pets"].Get.OperationID)
+			assert.Equal(t, "object", oapi.Components.Schemas["Pet"].Type)
+			id, ok := oapi.Components.Schemas["Pet"].Properties.Get("id")
+			strictID := id.(orderedmap.OrderedMap)
+			propertyType, _ := strictID.Get("type")
+			assert.True(t, ok)
+			assert.Equal(t, "integer", propertyType)
+		})
+	}
+}
+
+func TestSplitOutput(t *testing.T) {
+	createOAS := func(output, mode string) (*string, error) {
+		options := integrationOptions()
+		options.Format = FormatYAML
+		g, err := New(options)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := g.Build(context.Background()); err != nil {
+			return nil, err
+		}
+		return g.Write(output, mode)
+	}
+
+	dir := t.TempDir()
+	_, err := createOAS(dir, ModeDirWriter)
+	assert.NoError(t, err)
+	for _, file := range []string{"openapi.yaml", "paths/pets.yaml", "components/schemas/Pet.yaml"} {
+		assert.FileExists(t, filepath.Join(dir, file))
+	}
+
+	bundled, err := types.BundleDocument(filepath.Join(dir, "openapi.yaml"))
+	assert.NoError(t, err)
+	b, err := json.Marshal(bundled)
+	assert.NoError(t, err)
+
+	single, err := createOAS("", ModeTest)
+	assert.NoError(t, err)
+	var document yaml.MapSlice
+	assert.NoError(t, yaml.Unmarshal([]byte(*single), &document))
+	expected, err := json.Marshal(types.Document(document))
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(b))
+}
+
+func TestCheckOutput(t *testing.T) {
+	document, _, err := Generate(context.Background(), integrationOptions())
+	assert.NoError(t, err)
+	output, err := WriteDocument(document, "", ModeTest, FormatJSON)
+	assert.NoError(t, err)
+
+	// the file is formatted differently, as yaml with its keys in another order
+	var decoded interface{}
+	assert.NoError(t, yaml.Unmarshal([]byte(*output), &decoded))
+	formatted, err := yaml.Marshal(decoded)
+	assert.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "oas.yaml")
+	assert.NoError(t, os.WriteFile(file, formatted, 0o644))
+	_, err = WriteDocument(document, file, ModeCheck, FormatJSON)
+	assert.NoError(t, err)
+
+	stale := strings.Replace(string(formatted), "List all pets", "List pets", 1)
+	assert.NoError(t, os.WriteFile(file, []byte(stale), 0o644))
+	_, err = WriteDocument(document, file, ModeCheck, FormatJSON)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), file)
+	}
+
+	_, err = WriteDocument(document, filepath.Join(t.TempDir(), "missing.yaml"), ModeCheck, FormatJSON)
+	assert.Error(t, err)
+}
+
+func TestPackages(t *testing.T) {
+	path, _ := util.ModulePath("./").Get()
+	tests := map[string]struct {
+		options        Options
+		wantOperations []string
+		wantErr        bool
+	}{
+		"handler path": {
+			options:        Options{HandlerPaths: []string{"test/integration/pkg"}},
+			wantOperations: []string{"test/integration/pkg/integration_handler"},
+		},
+		"directory patterns": {
+			options:        Options{IncludePackages: []string{"./test/..."}, ExcludePackages: []string{"./test/unit", "./test/resolve/..."}},
+			wantOperations: []string{"test/integration", "test/integration/pkg/integration_handler"},
+		},
+		"import path globs": {
+			options:        Options{ExcludePackages: []string{path, path + "/pkg/*", path + "/internal/...", path + "/test/integration/**", "**/models"}},
+			wantOperations: []string{"test/resolve", "test/unit"},
+		},
+		"included and excluded": {
+			options:        Options{HandlerPaths: []string{"test/resolve"}, IncludePackages: []string{path + "/test/unit"}, ExcludePackages: []string{"**/internal/**"}},
+			wantOperations: []string{"test/resolve", "test/resolve/api/models", "test/unit"},
+		},
+		"invalid pattern": {
+			options: Options{IncludePackages: []string{"./[test"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := New(tc.options)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			operations, typesOnly, err := g.Packages(context.Background())
+			assert.NoError(t, err)
+
+			got := []string{}
+			for _, operation := range operations {
+				got = append(got, strings.TrimPrefix(strings.TrimPrefix(operation.ImportPath, path), "/"))
+			}
+			assert.Equal(t, tc.wantOperations, got)
+			assert.Len(t, typesOnly, 10-len(operations))
+		})
+	}
+}
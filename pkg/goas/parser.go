@@ -0,0 +1,3056 @@
+package goas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	goparser "go/parser"
+	"go/token"
+	gotypes "go/types"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+
+	"github.com/deanstalker/goas/pkg/types"
+
+	"github.com/deanstalker/goas/internal/util"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type parser struct {
+	ModulePath string
+	ModuleName string
+
+	MainFilePath string
+
+	// Include are the packages of the module operations are parsed in, the whole module if there are none, and
+	// Exclude the packages they are not parsed in. Packages that are not included are only parsed for their types.
+	Include []util.PackagePattern
+	Exclude []util.PackagePattern
+
+	GoModFilePath string
+
+	OpenAPI types.OpenAPIObject
+
+	KnownPkgs         []pkg
+	KnownIDSchema     map[string]*types.SchemaObject
+	KnownOperationIDs []string
+
+	// SchemaNaming is the strategy used to name component schemas, see util.SchemaNaming*
+	SchemaNaming  string
+	SchemaIDTypes map[string]*gotypes.TypeName
+	TypeSchemaIDs map[*gotypes.TypeName]string
+
+	// EmbeddedAllOf composes structs from the schemas of their embedded structs with allOf, instead of promoting their fields
+	EmbeddedAllOf bool
+
+	// TypeMappings overrides the schema of types, keyed by their import path and name
+	TypeMappings map[string]types.TypeMapping
+
+	// Nullable documents pointer, slice and map fields as nullable, as encoding/json marshals their nil values as null
+	Nullable bool
+
+	// OpenAPIVersion of the document, see util.OpenAPIVersion*
+	OpenAPIVersion string
+
+	// PathOrder of the document, see util.PathOrder*, ParsedPaths and ParsedWebhooks are in the order their routes were parsed
+	PathOrder      string
+	ParsedPaths    []string
+	ParsedWebhooks []string
+
+	// Diagnostics collected while generating the document, they are reported once it is built
+	Diagnostics []types.Diagnostic
+
+	// AllErrors collects the errors of every annotation that fails to parse as Diagnostics, instead of failing at the
+	// first
+	AllErrors bool
+
+	// BestEffort documents the types whose declaration cannot be found as empty schemas, with a warning, instead of
+	// failing
+	BestEffort bool
+
+	// DeclarePathParams declares the parameters of route templates that are not declared with @Param as required
+	// strings, instead of failing
+	DeclarePathParams bool
+
+	// Strict fails the generation of documents that violate the OpenAPI specification, instead of reporting the
+	// violations as warnings
+	Strict bool
+
+	// LintSeverities overrides the severity of lint rules, keyed by their id
+	LintSeverities map[string]string
+
+	// Sources maps the json pointers of the document to the annotations and declarations they were generated from
+	Sources map[string]token.Position
+
+	// Fset and Packages hold the type-checked module and all of its dependencies, keyed by import path
+	Fset     *token.FileSet
+	Packages map[string]*packages.Package
+
+	// TypeDecls maps each declared type to the ast.TypeSpec it was declared by, and ConstDecls each constant to its ast.ValueSpec
+	TypeDecls  map[*gotypes.TypeName]*ast.TypeSpec
+	ConstDecls map[*gotypes.Const]*ast.ValueSpec
+
+	// OperationScope is the scope of the handler func whose comments are being parsed
+	OperationScope *gotypes.Scope
+
+	// Pos is the position of the annotation or struct field being parsed
+	Pos token.Pos
+
+	Debug bool
+}
+
+type pkg struct {
+	Name string
+	Path string
+}
+
+func newParser(modulePath util.ModulePath, mainFilePath, handlerPath, excludePackages string, debug bool) (*parser, error) {
+	p := &parser{
+		KnownPkgs:      []pkg{},
+		KnownIDSchema:  map[string]*types.SchemaObject{},
+		SchemaNaming:   util.SchemaNamingShort,
+		SchemaIDTypes:  map[string]*gotypes.TypeName{},
+		TypeSchemaIDs:  map[*gotypes.TypeName]string{},
+		Packages:       map[string]*packages.Package{},
+		TypeDecls:      map[*gotypes.TypeName]*ast.TypeSpec{},
+		ConstDecls:     map[*gotypes.Const]*ast.ValueSpec{},
+		TypeMappings:   map[string]types.TypeMapping{},
+		OpenAPIVersion: util.OpenAPIVersion30,
+		PathOrder:      util.PathOrderSorted,
+		Sources:        map[string]token.Position{},
+		Fset:           token.NewFileSet(),
+		Debug:          debug,
+	}
+	p.AddTypeMappings(types.DefaultTypeMappings)
+	p.OpenAPI.OpenAPI = types.OpenAPIVersion
+	p.OpenAPI.Paths = make(types.PathsObject)
+	p.OpenAPI.Security = []map[string][]string{}
+	p.OpenAPI.Components.Schemas = make(map[string]*types.SchemaObject)
+	p.OpenAPI.Components.SecuritySchemes = map[string]*types.SecuritySchemeObject{}
+
+	// check modulePath exists
+	mp, err := modulePath.CheckPathExists()
+	if err != nil {
+		return nil, p.Errorf("error.parser.check-path-failed", "module", err)
+	}
+	p.ModulePath = mp
+
+	// check go.mod file exists
+	goModFilePath, goModFileInfo, err := modulePath.CheckGoModExists()
+	if err != nil {
+		return nil, p.Errorf("error.parser.check-file-failed", "go.mod", err)
+	}
+	p.GoModFilePath = goModFilePath
+
+	// check mainFilePath is exist
+	mainFilePath, err = modulePath.CheckMainFilePathExists(mainFilePath)
+	if err != nil {
+		return nil, p.Errorf("error.parser.check-path-failed", "main file", err)
+	}
+	p.MainFilePath = mainFilePath
+	p.Sources[""] = token.Position{Filename: mainFilePath}
+
+	// get module name from go.mod file
+	moduleName, err := modulePath.Get()
+	if err != nil {
+		return nil, p.Errorf("error.parser.get-module-name-failed", "go.mod file", err)
+	}
+	if moduleName == "" {
+		return nil, p.Errorf("error.parser.get-module-name-failed", goModFileInfo, nil)
+	}
+	p.ModuleName = moduleName
+
+	if handlerPath != "" {
+		if err := p.addHandlerPath(handlerPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.addPatterns(&p.Exclude, strings.Split(excludePackages, ",")); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// addHandlerPath includes the packages in a directory, and its subdirectories
+func (p *parser) addHandlerPath(handlerPath string) error {
+	handlerPath, _ = filepath.Abs(handlerPath)
+	_, err := os.Stat(handlerPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return err
+		}
+		return p.Errorf("error.io.stat-error", handlerPath, err)
+	}
+	return p.addPatterns(&p.Include, []string{filepath.Join(handlerPath, "...")})
+}
+
+// addPatterns compiles package patterns, relative to the working directory, see util.PackagePattern
+func (p *parser) addPatterns(patterns *[]util.PackagePattern, values []string) error {
+	compiled, err := util.NewPackagePatterns(values, ".")
+	if err != nil {
+		return p.Errorf("error.parser.invalid-pattern", err)
+	}
+	*patterns = append(*patterns, compiled...)
+	return nil
+}
+
+// parsesOperations reports whether operations are parsed in a package of the module, by its import path and directory
+func (p *parser) parsesOperations(importPath, dir string) bool {
+	for _, pattern := range p.Exclude {
+		if pattern.Match(importPath, dir) {
+			return false
+		}
+	}
+	if len(p.Include) == 0 {
+		return true
+	}
+	for _, pattern := range p.Include {
+		if pattern.Match(importPath, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// build parses the module into the OpenAPI 3.0 document, and validates it
+func (p *parser) build(ctx context.Context) error {
+	comments, err := p.parseFileComments()
+	if err != nil {
+		return err
+	}
+
+	// parse basic info
+	err = p.parseInfo(comments)
+	if err != nil {
+		return err
+	}
+
+	// load and type-check the module's packages
+	err = p.parseModule(ctx)
+	if err != nil {
+		return err
+	}
+
+	// parse APIs info
+	err = p.parseAPIs()
+	if err != nil {
+		return err
+	}
+	p.nameSchemas()
+
+	if count := p.countDiagnostics(types.SeverityError); count > 0 {
+		return p.Errorf("error.parser.errors", count)
+	}
+	return p.validateDocument()
+}
+
+// convertDocument returns the document that is built in the order of its paths, converted to swagger if it is
+// generated as 2.0. Documents generated as 3.1 are converted once they are built, see Generator.Build.
+func (p *parser) convertDocument() (interface{}, error) {
+	var document interface{} = p.OpenAPI
+	switch p.OpenAPIVersion {
+	case util.OpenAPIVersion20:
+		swagger, warnings := types.ConvertToSwagger(&p.OpenAPI)
+		for _, warning := range warnings {
+			p.warnf(token.NoPos, "warning.swagger.unsupported", warning.Location, warning.Construct)
+		}
+		document = swagger
+	}
+
+	if p.PathOrder != util.PathOrderSource {
+		return document, nil
+	}
+	ordered, err := types.NewDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	ordered.OrderKeys("paths", p.ParsedPaths)
+	ordered.OrderKeys("webhooks", p.ParsedWebhooks)
+	return ordered, nil
+}
+
+// validateDocument reports the violations of the OpenAPI specification in the document, with the location of the
+// annotation or declaration each was generated from. They fail the generation in strict mode.
+func (p *parser) validateDocument() error {
+	for id, typeObj := range p.SchemaIDTypes {
+		p.addSource(types.JSONPointer("components", "schemas", id), typeObj.Pos())
+	}
+
+	violations, err := types.ValidateOpenAPI(&p.OpenAPI)
+	if err != nil {
+		return err
+	}
+	severity := types.SeverityWarning
+	if p.Strict {
+		severity = types.SeverityError
+	}
+	for _, violation := range violations {
+		p.Diagnostics = append(p.Diagnostics, types.Diagnostic{
+			Position: p.sourcePosition(violation.Pointer),
+			Severity: severity,
+			Code:     diagnosticCode("warning.validate.violation"),
+			Message:  gotext.Get("warning.validate.violation", violation.Pointer, violation.Message),
+		})
+	}
+	if p.Strict && len(violations) > 0 {
+		return p.Errorf("error.validate.violations", len(violations))
+	}
+	return nil
+}
+
+// addSource records the position a json pointer of the document was generated from
+func (p *parser) addSource(pointer string, pos token.Pos) {
+	if p.Fset == nil || !pos.IsValid() {
+		return
+	}
+	p.Sources[pointer] = p.Fset.Position(pos)
+}
+
+// sourcePosition returns the position of the closest value a json pointer is nested in that has one, relative to
+// the module
+func (p *parser) sourcePosition(pointer string) token.Position {
+	for {
+		if position, ok := p.Sources[pointer]; ok {
+			return p.relativePosition(position)
+		}
+		i := strings.LastIndex(pointer, "/")
+		if i < 0 {
+			return token.Position{Filename: p.MainFilePath}
+		}
+		pointer = pointer[:i]
+	}
+}
+
+// relativePosition returns a position with its file relative to the module, if it is in the module
+func (p *parser) relativePosition(position token.Position) token.Position {
+	if rel, err := filepath.Rel(p.ModulePath, position.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+		position.Filename = rel
+	}
+	return position
+}
+
+// Errorf returns the error of a msgid as a diagnostic, coded by the msgid
+func (p *parser) Errorf(msgid string, args ...interface{}) error {
+	return errorf(msgid, args...)
+}
+
+func errorf(msgid string, args ...interface{}) error {
+	return types.Diagnostic{
+		Severity: types.SeverityError,
+		Code:     diagnosticCode(msgid),
+		Message:  gotext.Get(msgid, args...),
+	}
+}
+
+// wrapErrorf returns the error of msgid for err, which is the last argument of msgid. A diagnostic err is the cause of
+// the error, it keeps its code and position so that it is reported as what failed, where it failed.
+func (p *parser) wrapErrorf(err error, msgid string, args ...interface{}) error {
+	var cause types.Diagnostic
+	if !errors.As(err, &cause) {
+		return p.Errorf(msgid, append(args, err)...)
+	}
+	cause.Message = gotext.Get(msgid, append(args, cause.Message)...)
+	return cause
+}
+
+// errorfAt returns the error of a msgid as a diagnostic located at pos
+func (p *parser) errorfAt(pos token.Pos, msgid string, args ...interface{}) error {
+	return p.locate(pos, p.Errorf(msgid, args...))
+}
+
+// warnf collects the warning of a msgid as a diagnostic located at pos
+func (p *parser) warnf(pos token.Pos, msgid string, args ...interface{}) {
+	p.Diagnostics = append(p.Diagnostics, types.Diagnostic{
+		Position: p.position(pos),
+		Severity: types.SeverityWarning,
+		Code:     diagnosticCode(msgid),
+		Message:  gotext.Get(msgid, args...),
+	})
+}
+
+// locate returns an error as a diagnostic located at pos, unless it is located already. Errors that are not
+// diagnostics are coded as parser.failed.
+func (p *parser) locate(pos token.Pos, err error) error {
+	var diagnostic types.Diagnostic
+	if !errors.As(err, &diagnostic) {
+		diagnostic = types.Diagnostic{Severity: types.SeverityError, Code: "parser.failed", Message: err.Error()}
+	}
+	if diagnostic.Position.Filename == "" {
+		diagnostic.Position = p.position(pos)
+	}
+	return diagnostic
+}
+
+// fail locates the error of the annotation at pos. With AllErrors it is collected and nil is returned, so that
+// parsing goes on with the next annotation.
+func (p *parser) fail(pos token.Pos, err error) error {
+	err = p.locate(pos, err)
+	if !p.AllErrors {
+		return err
+	}
+	p.Diagnostics = append(p.Diagnostics, err.(types.Diagnostic))
+	return nil
+}
+
+// position of pos relative to the module, or no position if it is unknown
+func (p *parser) position(pos token.Pos) token.Position {
+	if p.Fset == nil || !pos.IsValid() {
+		return token.Position{}
+	}
+	return p.relativePosition(p.Fset.Position(pos))
+}
+
+// countDiagnostics returns the number of diagnostics collected with a severity
+func (p *parser) countDiagnostics(severity string) int {
+	count := 0
+	for _, diagnostic := range p.Diagnostics {
+		if diagnostic.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// diagnosticCode of a msgid is the msgid without its severity
+func diagnosticCode(msgid string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(msgid, "error."), "warning.")
+}
+
+func (p *parser) parseFileComments() ([]*ast.CommentGroup, error) {
+	fileTree, err := goparser.ParseFile(p.Fset, p.MainFilePath, nil, goparser.ParseComments)
+	if err != nil {
+		return nil, p.Errorf("error.parser.parse-file-comments-failed", err)
+	}
+
+	return fileTree.Comments, nil
+}
+
+func (p *parser) parseSchemaComments(comments []*ast.Comment, schemaObject *types.SchemaObject) {
+	for i := range comments {
+		for _, comment := range strings.Split(comments[i].Text, "\n") {
+			comment = strings.TrimSpace(strings.Trim(comment, "/"))
+			attribute := strings.ToLower(strings.Split(comment, " ")[0])
+			if attribute == "" || attribute[0] != '@' {
+				continue
+			}
+			value := strings.TrimSpace(comment[len(attribute):])
+			if value == "" {
+				continue
+			}
+			switch attribute {
+			case types.AttributeTitle:
+				schemaObject.Title = value
+			case types.AttributeDescription:
+				schemaObject.Description = value
+			case types.AttributeSchema:
+				fields := strings.Fields(value)
+				schemaObject.Type = fields[0]
+				if len(fields) > 1 {
+					schemaObject.Format = fields[1]
+				}
+			}
+		}
+	}
+}
+
+func (p *parser) parseInfo(comments []*ast.CommentGroup) error {
+	// Security Scopes are defined at a different level in the hierarchy as where they need to end up in the OpenAPI structure,
+	// so a temporary list is needed.
+	oauthScopes := make(map[string]map[string]string)
+
+	for i := range comments {
+		for _, line := range commentLines(comments[i].List) {
+			if err := p.parseInfoComment(line.Text, line.Pos, oauthScopes); err != nil {
+				if err := p.fail(line.Pos, err); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Apply security scopes to their security schemes
+	p.applySecurityScopes(oauthScopes)
+
+	if err := p.validateInfo(); err != nil {
+		diagnostic := p.locate(token.NoPos, err).(types.Diagnostic)
+		diagnostic.Position = p.sourcePosition("/info")
+		return diagnostic
+	}
+
+	return nil
+}
+
+// commentLine is a line of text of a comment, and the position it starts at
+type commentLine struct {
+	Text string
+	Pos  token.Pos
+}
+
+// commentLines splits comments into their lines of text, without comment markers
+func commentLines(comments []*ast.Comment) []commentLine {
+	var lines []commentLine
+	for _, comment := range comments {
+		if strings.HasPrefix(comment.Text, "//") {
+			lines = append(lines, commentLine{Text: strings.TrimSpace(comment.Text[2:]), Pos: comment.Slash})
+			continue
+		}
+		text := strings.TrimSuffix(strings.TrimPrefix(comment.Text, "/*"), "*/")
+		offset := 2
+		for _, line := range strings.Split(text, "\n") {
+			lines = append(lines, commentLine{Text: strings.TrimSpace(line), Pos: comment.Slash + token.Pos(offset)})
+			offset += len(line) + 1
+		}
+	}
+	return lines
+}
+
+// parseInfoComment parses an annotation of the main file at pos
+func (p *parser) parseInfoComment(comment string, pos token.Pos, oauthScopes map[string]map[string]string) error {
+	attribute := strings.ToLower(strings.Split(comment, " ")[0])
+	if attribute == "" || attribute[0] != '@' {
+		return nil
+	}
+	value := strings.TrimSpace(comment[len(attribute):])
+	if value == "" {
+		return nil
+	}
+	switch attribute {
+	case types.AttributeVersion:
+		p.OpenAPI.Info.Version = value
+	case types.AttributeTitle:
+		p.OpenAPI.Info.Title = value
+	case types.AttributeDescription:
+		p.OpenAPI.Info.Description = value
+	case types.AttributeSummary:
+		if p.requireOpenAPI31(pos, attribute) {
+			p.OpenAPI.Info.Summary = value
+		}
+	case types.AttributeTOSURL:
+		p.OpenAPI.Info.TermsOfService = value
+	case types.AttributeContactName:
+		if p.OpenAPI.Info.Contact == nil {
+			p.OpenAPI.Info.Contact = &types.ContactObject{}
+		}
+		p.OpenAPI.Info.Contact.Name = value
+	case types.AttributeContactEmail:
+		if p.OpenAPI.Info.Contact == nil {
+			p.OpenAPI.Info.Contact = &types.ContactObject{}
+		}
+		p.OpenAPI.Info.Contact.Email = value
+	case types.AttributeContactURL:
+		if p.OpenAPI.Info.Contact == nil {
+			p.OpenAPI.Info.Contact = &types.ContactObject{}
+		}
+		p.OpenAPI.Info.Contact.URL = value
+	case types.AttributeLicenseName:
+		if p.OpenAPI.Info.License == nil {
+			p.OpenAPI.Info.License = &types.LicenseObject{}
+		}
+		p.OpenAPI.Info.License.Name = value
+	case types.AttributeLicenseURL:
+		if p.OpenAPI.Info.License == nil {
+			p.OpenAPI.Info.License = &types.LicenseObject{}
+		}
+		p.OpenAPI.Info.License.URL = value
+	case types.AttributeLicenseIdentifier:
+		if !p.requireOpenAPI31(pos, attribute) {
+			return nil
+		}
+		if p.OpenAPI.Info.License == nil {
+			p.OpenAPI.Info.License = &types.LicenseObject{}
+		}
+		p.OpenAPI.Info.License.Identifier = value
+	case types.AttributeServer:
+		fields := strings.Split(value, " ")
+		_, err := url.ParseRequestURI(fields[0])
+		// allow server variable tokens through
+		if err != nil && !strings.Contains(fields[0], "{") {
+			return p.Errorf(`error.parser.invalid-url`, fields[0])
+		}
+		s := types.ServerObject{
+			URL:         fields[0],
+			Description: strings.TrimSpace(value[len(fields[0]):]),
+		}
+		p.OpenAPI.Servers = append(p.OpenAPI.Servers, s)
+	case types.AttributeSecurity:
+		fields := strings.Split(value, " ")
+		security := map[string][]string{
+			fields[0]: fields[1:],
+		}
+		p.OpenAPI.Security = append(p.OpenAPI.Security, security)
+	case types.AttributeSecurityScheme:
+		p.parseSecurityScheme(value)
+	case types.AttributeSecurityScope:
+		fields := strings.Split(value, " ")
+
+		if _, ok := oauthScopes[fields[0]]; !ok {
+			oauthScopes[fields[0]] = make(map[string]string)
+		}
+
+		oauthScopes[fields[0]][fields[1]] = strings.Join(fields[2:], " ")
+	case types.AttributeExternalDoc:
+		externalDocs, err := p.parseExternalDocComment(strings.TrimSpace(comment[len(attribute):]))
+		if err != nil {
+			return err
+		}
+		if externalDocs == nil {
+			return p.Errorf("error.parser.could-not-populate", types.AttributeExternalDoc)
+		}
+
+		p.OpenAPI.ExternalDocs = externalDocs
+	case types.AttributeTag:
+		tag, err := p.parseTagComment(strings.TrimSpace(comment[len(attribute):]))
+		if err != nil {
+			return fmt.Errorf("%v", err)
+		}
+
+		p.OpenAPI.Tags = append(p.OpenAPI.Tags, *tag)
+	case types.AttributeServerVariable:
+		for i, server := range p.OpenAPI.Servers {
+			if server.Variables == nil {
+				server.Variables = make(map[string]types.ServerVariableObject)
+			}
+			server.Variables, _ = p.parseServerVariableComment(comment, server)
+
+			p.OpenAPI.Servers[i] = server
+		}
+	}
+	return nil
+}
+
+func (p *parser) validateInfo() error {
+	if p.OpenAPI.Info.Title == "" {
+		return p.Errorf("error.parser.required-comment", "info.title")
+	}
+	if p.OpenAPI.Info.Version == "" {
+		return p.Errorf("error.parser.required-comment", "info.version")
+	}
+	for i := range p.OpenAPI.Servers {
+		if p.OpenAPI.Servers[i].URL == "" {
+			return p.Errorf("error.parser.required-comment", fmt.Sprintf("servers[%d].url", i))
+		}
+	}
+	return nil
+}
+
+func (p *parser) applySecurityScopes(oauthScopes map[string]map[string]string) {
+	for scheme := range p.OpenAPI.Components.SecuritySchemes {
+		if p.OpenAPI.Components.SecuritySchemes[scheme].Type == "oauth2" {
+			if scopes, ok := oauthScopes[scheme]; ok {
+				p.OpenAPI.Components.SecuritySchemes[scheme].OAuthFlows.ApplyScopes(scopes)
+			}
+		}
+	}
+}
+
+func (p *parser) parseModule(ctx context.Context) error {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Context:   ctx,
+		Dir:       p.ModulePath,
+		Fset:      p.Fset,
+		ParseFile: p.parseFile,
+	}
+	roots, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return p.Errorf("error.parser.package-load-error", p.ModulePath, err)
+	}
+
+	packages.Visit(roots, nil, func(loaded *packages.Package) {
+		p.Packages[loaded.PkgPath] = loaded
+		if p.Debug {
+			for _, e := range loaded.Errors {
+				log.Printf("%s: %v", loaded.PkgPath, e)
+			}
+		}
+	})
+
+	p.KnownPkgs, _ = p.modulePackages(roots)
+
+	return nil
+}
+
+// listPackages loads the packages of the module without type-checking them, see modulePackages
+func (p *parser) listPackages(ctx context.Context) (operations, typesOnly []pkg, err error) {
+	cfg := &packages.Config{
+		Mode:    packages.NeedName | packages.NeedFiles,
+		Context: ctx,
+		Dir:     p.ModulePath,
+	}
+	roots, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, nil, p.Errorf("error.parser.package-load-error", p.ModulePath, err)
+	}
+	operations, typesOnly = p.modulePackages(roots)
+	return operations, typesOnly, nil
+}
+
+// modulePackages splits the packages of the module into the packages operations are parsed in, and the packages
+// that are only parsed for their types, sorted by their directory
+func (p *parser) modulePackages(roots []*packages.Package) (operations, typesOnly []pkg) {
+	for _, root := range roots {
+		if len(root.GoFiles) == 0 {
+			continue
+		}
+		known := pkg{
+			Name: root.PkgPath,
+			Path: filepath.Dir(root.GoFiles[0]),
+		}
+		if p.parsesOperations(known.Name, known.Path) {
+			operations = append(operations, known)
+		} else {
+			typesOnly = append(typesOnly, known)
+		}
+	}
+	for _, pkgs := range [][]pkg{operations, typesOnly} {
+		sort.Slice(pkgs, func(i, j int) bool {
+			return pkgs[i].Path < pkgs[j].Path
+		})
+	}
+	return operations, typesOnly
+}
+
+// parseFile keeps comments for every file, but drops function bodies outside of the module
+// as only declarations are needed from dependencies
+func (p *parser) parseFile(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	astFile, err := goparser.ParseFile(fset, filename, src, goparser.ParseComments)
+	if astFile == nil || strings.HasPrefix(filename, p.ModulePath+string(filepath.Separator)) {
+		return astFile, err
+	}
+	for _, astDeclaration := range astFile.Decls {
+		if astFuncDeclaration, ok := astDeclaration.(*ast.FuncDecl); ok {
+			astFuncDeclaration.Body = nil
+		}
+	}
+	return astFile, err
+}
+
+func (p *parser) parseAPIs() error {
+	err := p.parseTypeSpecs()
+	if err != nil {
+		return err
+	}
+
+	return p.parsePaths()
+}
+
+// parseTypeSpecs links the type-checker's objects back to their declarations, including types declared in funcs,
+// and the constants that may enumerate their values
+func (p *parser) parseTypeSpecs() error {
+	for _, loaded := range p.Packages {
+		if loaded.TypesInfo == nil {
+			continue
+		}
+		for _, astFile := range loaded.Syntax {
+			ast.Inspect(astFile, func(node ast.Node) bool {
+				astGenDeclaration, ok := node.(*ast.GenDecl)
+				if !ok {
+					return true
+				}
+				if astGenDeclaration.Tok == token.CONST {
+					p.findConstDeclaration(loaded, astGenDeclaration)
+					return true
+				}
+				if astGenDeclaration.Tok != token.TYPE {
+					return true
+				}
+				for _, astSpec := range astGenDeclaration.Specs {
+					typeSpec, ok := astSpec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if typeSpec.Doc == nil {
+						typeSpec.Doc = astGenDeclaration.Doc // assign the gendec Doc block to the typeSpec docblock
+					}
+					if typeName, ok := loaded.TypesInfo.Defs[typeSpec.Name].(*gotypes.TypeName); ok {
+						p.TypeDecls[typeName] = typeSpec
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) findConstDeclaration(loaded *packages.Package, astGenDeclaration *ast.GenDecl) {
+	for _, astSpec := range astGenDeclaration.Specs {
+		valueSpec, ok := astSpec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		if valueSpec.Doc == nil && len(astGenDeclaration.Specs) == 1 {
+			valueSpec.Doc = astGenDeclaration.Doc
+		}
+		for _, astName := range valueSpec.Names {
+			if constObj, ok := loaded.TypesInfo.Defs[astName].(*gotypes.Const); ok {
+				p.ConstDecls[constObj] = valueSpec
+			}
+		}
+	}
+}
+
+func (p *parser) parsePaths() error {
+	for i := range p.KnownPkgs {
+		pkgPath := p.KnownPkgs[i].Path
+		pkgName := p.KnownPkgs[i].Name
+
+		loaded, ok := p.Packages[pkgName]
+		if !ok {
+			return p.Errorf("error.parser.package-parse-error", "parsePaths", pkgPath, "package was not loaded")
+		}
+		for _, astFile := range loaded.Syntax {
+			for _, astDeclaration := range astFile.Decls {
+				if astFuncDeclaration, ok := astDeclaration.(*ast.FuncDecl); ok {
+					if astFuncDeclaration.Doc != nil && astFuncDeclaration.Doc.List != nil {
+						if loaded.TypesInfo != nil {
+							p.OperationScope = loaded.TypesInfo.Scopes[astFuncDeclaration.Type]
+						}
+						err := p.parseOperation(pkgPath, pkgName, astFuncDeclaration.Doc.List)
+						p.OperationScope = nil
+						if err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func isHidden(astComments []*ast.Comment) bool {
+	for _, astComment := range astComments {
+		comment := strings.TrimSpace(strings.TrimLeft(astComment.Text, "/"))
+		if comment == "" {
+			// ignore empty lines
+			continue
+		}
+		attribute := strings.Fields(comment)[0]
+		if strings.EqualFold(attribute, types.AttributeHidden) {
+			return true
+		}
+	}
+	return false
+}
+
+// operationSources are the positions of the annotations of an operation
+type operationSources struct {
+	// routes and webhooks of the operation in the order they are annotated
+	routes []operationRoute
+	// values are keyed by the json pointer of what the other annotations generated, relative to the operation
+	values map[string]token.Pos
+}
+
+// operationRoute is a route, or webhook, an operation is added to
+type operationRoute struct {
+	// pointer is the json pointer of the operation the route generated
+	pointer string
+	// path and method of a route, the path is empty for webhooks
+	path, method string
+	pos          token.Pos
+}
+
+// paths of the routes in the order they are annotated, with the position of the first route of each
+func (s *operationSources) paths() ([]string, map[string]token.Pos) {
+	var paths []string
+	pathSources := map[string]token.Pos{}
+	for _, route := range s.routes {
+		if _, ok := pathSources[route.path]; route.path != "" && !ok {
+			paths = append(paths, route.path)
+			pathSources[route.path] = route.pos
+		}
+	}
+	return paths, pathSources
+}
+
+func (p *parser) parseOperation(pkgPath, pkgName string, astComments []*ast.Comment) error {
+	operation := &types.OperationObject{
+		Responses: map[string]*types.ResponseObject{},
+	}
+	if !strings.HasPrefix(pkgPath, p.ModulePath) {
+		return nil
+	}
+	if isHidden(astComments) {
+		return nil
+	}
+	sources := &operationSources{
+		values: map[string]token.Pos{},
+	}
+	for _, astComment := range astComments {
+		comment := strings.TrimSpace(strings.TrimLeft(astComment.Text, "/"))
+		if comment == "" {
+			// ignore empty lines
+			continue
+		}
+		p.Pos = astComment.Slash
+		err := p.parseOperationComment(pkgPath, pkgName, operation, comment, astComment.Slash, sources)
+		p.Pos = token.NoPos
+		if err != nil {
+			if err := p.fail(astComment.Slash, err); err != nil {
+				return err
+			}
+		}
+	}
+	// each route of an operation with more than one route is documented with the path parameters of its own template
+	paths, pathSources := sources.paths()
+	templated := map[string]bool{}
+	for _, path := range paths {
+		for name := range templateParameters(path) {
+			templated[name] = true
+		}
+	}
+	pathValues := map[string]map[string]token.Pos{}
+	for _, path := range paths {
+		pathOperation, values := operation, sources.values
+		if len(paths) > 1 {
+			pathOperation, values = routeOperation(operation, path, templated, sources.values)
+			for _, route := range sources.routes {
+				if route.path == path {
+					p.OpenAPI.Paths[path].SetOperation(route.method, pathOperation)
+				}
+			}
+		}
+		pathValues[path] = values
+		if err := p.checkPathParameters(path, pathOperation, pathSources[path], values); err != nil {
+			if err := p.fail(pathSources[path], err); err != nil {
+				return err
+			}
+		}
+	}
+	for _, route := range sources.routes {
+		p.addSource(route.pointer, route.pos)
+		values, ok := pathValues[route.path]
+		if !ok {
+			values = sources.values
+		}
+		for pointer, pos := range values {
+			p.addSource(route.pointer+pointer, pos)
+		}
+	}
+	return nil
+}
+
+// parseOperationComment parses an annotation of an operation at pos, and records the position of what it generated
+func (p *parser) parseOperationComment(
+	pkgPath, pkgName string,
+	operation *types.OperationObject,
+	comment string,
+	pos token.Pos,
+	sources *operationSources,
+) error {
+	attribute := strings.Fields(comment)[0]
+	switch strings.ToLower(attribute) {
+	case types.AttributeTitle:
+		operation.Summary = strings.TrimSpace(comment[len(attribute):])
+	case types.AttributeDescription:
+		operation.Description = strings.TrimSpace(
+			strings.Join([]string{operation.Description, strings.TrimSpace(comment[len(attribute):])}, " "),
+		)
+	case types.AttributeParam:
+		parameters, hasRequestBody := len(operation.Parameters), operation.RequestBody != nil
+		if err := p.parseParamComment(pkgPath, pkgName, operation, strings.TrimSpace(comment[len(attribute):])); err != nil {
+			return err
+		}
+		if len(operation.Parameters) > parameters {
+			sources.values[types.JSONPointer("parameters", strconv.Itoa(parameters))] = pos
+		} else if !hasRequestBody && operation.RequestBody != nil {
+			sources.values[types.JSONPointer("requestBody")] = pos
+		}
+	case types.AttributeHeader:
+		if err := p.parseResponseHeader(pkgPath, pkgName, operation, strings.TrimSpace(comment[len(attribute):])); err != nil {
+			return err
+		}
+		if fields := strings.Fields(comment[len(attribute):]); len(fields) > 1 {
+			sources.values[types.JSONPointer("responses", fields[0], "headers", fields[1])] = pos
+		}
+	case types.AttributeSuccess, types.AttributeFailure:
+		if err := p.parseResponseComment(pkgPath, pkgName, operation, strings.TrimSpace(comment[len(attribute):])); err != nil {
+			return err
+		}
+		sources.values[types.JSONPointer("responses", strings.Fields(comment[len(attribute):])[0])] = pos
+	case types.AttributeID:
+		id := strings.TrimSpace(comment[len(attribute):])
+		if err := p.validateOperationID(id); err != nil {
+			return err
+		}
+		operation.OperationID = id
+	case types.AttributeExternalDoc:
+		externalDocs, err := p.parseExternalDocComment(strings.TrimSpace(comment[len(attribute):]))
+		if err != nil {
+			return err
+		}
+		if externalDocs == nil {
+			return p.Errorf("error.parser.could-not-populate", types.AttributeExternalDoc)
+		}
+
+		operation.ExternalDocs = externalDocs
+	case types.AttributeResource, types.AttributeTag:
+		resource := strings.TrimSpace(comment[len(attribute):])
+		if resource == "" {
+			resource = "others"
+		}
+		if !util.IsInStringList(operation.Tags, resource) {
+			operation.Tags = append(operation.Tags, resource)
+		}
+	case types.AttributeRoute, types.AttributeRouter:
+		path, method, err := p.parseRouteComment(operation, comment)
+		if err != nil {
+			return err
+		}
+		sources.routes = append(sources.routes, operationRoute{
+			pointer: types.JSONPointer("paths", path, method),
+			path:    path,
+			method:  method,
+			pos:     pos,
+		})
+	case types.AttributeWebhook:
+		if !p.requireOpenAPI31(pos, attribute) {
+			return nil
+		}
+		name, method, err := p.parseWebhookComment(operation, comment)
+		if err != nil {
+			return err
+		}
+		sources.routes = append(sources.routes, operationRoute{
+			pointer: types.JSONPointer("webhooks", name, method),
+			method:  method,
+			pos:     pos,
+		})
+	case types.AttributeSecurity:
+		security := strings.TrimSpace(comment[len(attribute):])
+		matches := strings.Split(security, " ")
+
+		operation.Security = append(operation.Security, map[string][]string{
+			matches[0]: {},
+		})
+	}
+	return nil
+}
+
+var routeTemplateParameter = regexp.MustCompile(`{([^}]+)}`)
+
+// checkPathParameters cross-checks the parameters of a route template with the path parameters of its operation.
+// Parameters missing from the template are errors, parameters missing from the operation are declared as required
+// strings with DeclarePathParams, and errors otherwise. Errors are located at the annotation they are caused by.
+func (p *parser) checkPathParameters(path string, operation *types.OperationObject, routePos token.Pos, sources map[string]token.Pos) error {
+	templated := templateParameters(path)
+
+	declared := map[string]bool{}
+	for i, parameter := range operation.Parameters {
+		if parameter.In != types.InPath {
+			continue
+		}
+		declared[parameter.Name] = true
+		if !templated[parameter.Name] {
+			pos := sources[types.JSONPointer("parameters", strconv.Itoa(i))]
+			return p.errorfAt(pos, "error.parser.unknown-path-parameter", parameter.Name, path)
+		}
+	}
+
+	for _, match := range routeTemplateParameter.FindAllStringSubmatch(path, -1) {
+		name := match[1]
+		if declared[name] {
+			continue
+		}
+		if !p.DeclarePathParams {
+			return p.errorfAt(routePos, "error.parser.undeclared-path-parameter", name, path)
+		}
+		operation.Parameters = append(operation.Parameters, types.ParameterObject{
+			Name:     name,
+			In:       types.InPath,
+			Required: true,
+			Schema:   &types.SchemaObject{Type: types.TypeString},
+		})
+		declared[name] = true
+	}
+	return nil
+}
+
+// templateParameters returns the names of the parameters of a route template
+func templateParameters(path string) map[string]bool {
+	templated := map[string]bool{}
+	for _, match := range routeTemplateParameter.FindAllStringSubmatch(path, -1) {
+		templated[match[1]] = true
+	}
+	return templated
+}
+
+// routeOperation copies an operation for the route of path, without the path parameters that are only in the
+// templates of its other routes, which are all templated. The positions of the parameters in sources are renumbered
+// to the parameters of the copy.
+func routeOperation(
+	operation *types.OperationObject,
+	path string,
+	templated map[string]bool,
+	sources map[string]token.Pos,
+) (*types.OperationObject, map[string]token.Pos) {
+	pathTemplated := templateParameters(path)
+	copied := *operation
+	copied.Parameters = nil
+	copiedSources := map[string]token.Pos{}
+	for pointer, pos := range sources {
+		if !strings.HasPrefix(pointer, types.JSONPointer("parameters")+"/") {
+			copiedSources[pointer] = pos
+		}
+	}
+	for i, parameter := range operation.Parameters {
+		if parameter.In == types.InPath && templated[parameter.Name] && !pathTemplated[parameter.Name] {
+			continue
+		}
+		if pos, ok := sources[types.JSONPointer("parameters", strconv.Itoa(i))]; ok {
+			copiedSources[types.JSONPointer("parameters", strconv.Itoa(len(copied.Parameters)))] = pos
+		}
+		copied.Parameters = append(copied.Parameters, parameter)
+	}
+	return &copied, copiedSources
+}
+
+func (p *parser) parseSecurityScheme(value string) {
+	// {key} http {in} {name} {description}
+	// {key} apiKey {in} {name} {description}
+	// {key} openIdConnect {connect_url} {description}
+	// {key} oauth2AuthCode {auth_url} {token_url}
+	// {key} oauth2Implicit {auth_url}
+	// {key} oauth2ResourceOwnerCredentials {token_url}
+	// {key} oauth2ClientCredentials {token_url}
+	fields := strings.Split(value, " ")
+
+	var scheme *types.SecuritySchemeObject
+	if strings.Contains(fields[1], "oauth2") {
+		if oauthScheme, ok := p.OpenAPI.Components.SecuritySchemes[fields[0]]; ok {
+			scheme = oauthScheme
+		} else {
+			scheme = &types.SecuritySchemeObject{
+				Type:       "oauth2",
+				OAuthFlows: &types.SecuritySchemeOauthObject{},
+			}
+		}
+	}
+
+	if scheme == nil {
+		scheme = &types.SecuritySchemeObject{
+			Type: fields[1],
+		}
+	}
+	switch fields[1] {
+	case "http":
+		scheme.Scheme = fields[2]
+		if scheme.Scheme == "bearer" {
+			scheme.Description = strings.Join(fields[3:], " ")
+		} else {
+			scheme.Name = fields[3]
+			scheme.Description = strings.Join(fields[4:], " ")
+		}
+	case "apiKey":
+		scheme.In = fields[2]
+		scheme.Name = fields[3]
+		scheme.Description = strings.Join(fields[4:], " ")
+	case "openIdConnect":
+		scheme.OpenIDConnectURL = fields[2]
+		scheme.Description = strings.Join(fields[3:], " ")
+	case "oauth2AuthCode":
+		scheme.OAuthFlows.AuthorizationCode = &types.SecuritySchemeOauthFlowObject{
+			AuthorizationURL: fields[2],
+			TokenURL:         fields[3],
+			Scopes:           make(map[string]string),
+		}
+	case "oauth2Implicit":
+		scheme.OAuthFlows.Implicit = &types.SecuritySchemeOauthFlowObject{
+			AuthorizationURL: fields[2],
+			Scopes:           make(map[string]string),
+		}
+	case "oauth2ResourceOwnerCredentials":
+		scheme.OAuthFlows.ResourceOwnerPassword = &types.SecuritySchemeOauthFlowObject{
+			TokenURL: fields[2],
+			Scopes:   make(map[string]string),
+		}
+	case "oauth2ClientCredentials":
+		scheme.OAuthFlows.ClientCredentials = &types.SecuritySchemeOauthFlowObject{
+			TokenURL: fields[2],
+			Scopes:   make(map[string]string),
+		}
+	}
+	if p.OpenAPI.Components.SecuritySchemes == nil {
+		p.OpenAPI.Components.SecuritySchemes = make(map[string]*types.SecuritySchemeObject)
+	}
+	p.OpenAPI.Components.SecuritySchemes[fields[0]] = scheme
+}
+
+func (p *parser) parseServerVariableComment(comment string, server types.ServerObject) (map[string]types.ServerVariableObject, error) {
+	// {name} {default} {description} {enum1,enum2,...}
+	re := regexp.MustCompile(`([-\w]+)[\s]+"([^"]+)"[\s]*(?:"([^"]+)"(?:[\s]+"([\w,^"]+)"|$))`)
+	matches := re.FindStringSubmatch(comment)
+	validSegments := 5
+	if len(matches) != validSegments {
+		return nil, p.Errorf("error.parser.can-not-parse-comment", "parseServerVariableComment", types.AttributeServerVariable, comment)
+	}
+
+	if !strings.Contains(server.URL, fmt.Sprintf(`{%s}`, matches[1])) {
+		return server.Variables, nil
+	}
+
+	serverVar := types.ServerVariableObject{
+		Enum:        nil,
+		Default:     matches[2],
+		Description: matches[3],
+	}
+
+	if matches[4] != "" {
+		enums := strings.Split(matches[4], ",")
+		serverVar.Enum = enums
+	}
+
+	server.Variables[matches[1]] = serverVar
+
+	return server.Variables, nil
+}
+
+func (p *parser) parseExternalDocComment(comment string) (*types.ExternalDocumentationObject, error) {
+	// {url}  {description}
+
+	re := regexp.MustCompile(`([\w?&#/:.]+)\s+"([^"]+)"`)
+	matches := re.FindStringSubmatch(comment)
+	validSegments := 3
+	if len(matches) != validSegments {
+		return nil, p.Errorf("error.parser.can-not-parse-comment", "parseExternalDocComment", types.AttributeExternalDoc, comment)
+	}
+	extURL := matches[1]
+	description := matches[2]
+
+	return &types.ExternalDocumentationObject{
+		Description: description,
+		URL:         extURL,
+	}, nil
+}
+
+func (p *parser) parseTagComment(comment string) (*types.TagObject, error) {
+	// {name} {description} {externalDocURL} {externalDocDesc}
+
+	re := regexp.MustCompile(`([-\w]+)\s+"([^"]+)"\s*(?:([\w?&#/:.]+)\s+"([^"]+)"|$)`)
+	matches := re.FindStringSubmatch(comment)
+
+	if len(matches) != 5 || matches[1] == "" || matches[2] == "" {
+		return nil, p.Errorf("error.parser.can-not-parse-comment", "parseTagComment", types.AttributeTag, comment)
+	}
+
+	tag := &types.TagObject{
+		Name:         matches[1],
+		Description:  matches[2],
+		ExternalDocs: nil,
+	}
+
+	if matches[3] != "" && matches[4] != "" {
+		tag.ExternalDocs = &types.ExternalDocumentationObject{
+			Description: matches[4],
+			URL:         matches[3],
+		}
+	}
+
+	return tag, nil
+}
+
+func (p *parser) parseParamComment(pkgPath, pkgName string, operation *types.OperationObject, comment string) error {
+	// {name}  {in}  {goType}  {required}  {description}
+	// user    body  User      true        "Info of a user."
+	// f       file  ignored   true        "Upload a file."
+	re := regexp.MustCompile(`([-\w]+)[\s]+([\w]+)[\s]+([\w./\[\]]+)[\s]+([\w]+)[\s]+"([^"]+)"`)
+	matches := re.FindStringSubmatch(comment)
+	validSegments := 6
+	if len(matches) != validSegments {
+		return p.Errorf("error.parser.can-not-parse-comment", "parseParamComment", types.AttributeParam, comment)
+	}
+	name := matches[1]
+	in := matches[2]
+
+	re = regexp.MustCompile(`\[\w*]`)
+	goType := re.ReplaceAllString(matches[3], "[]")
+
+	required := false
+	switch strings.ToLower(matches[4]) {
+	case "true", types.KeywordRequired:
+		required = true
+	}
+	description := matches[5]
+
+	// `file`, `form`
+	if ok := p.handleFileOrForm(name, in, operation, goType, description, required, pkgName); ok {
+		return nil
+	}
+
+	// `path`, `query`, `header`, `cookie`
+	if in != types.InBody {
+		if err := p.handleParam(name, in, operation, description, goType, required, pkgPath, pkgName); err != nil {
+			return p.wrapErrorf(err, "error.parser.unable-to-handle-params", "parseParamComment")
+		}
+		return nil
+	}
+
+	if operation.RequestBody == nil {
+		operation.RequestBody = &types.RequestBodyObject{
+			Content:  map[string]*types.MediaTypeObject{},
+			Required: required,
+		}
+	}
+
+	if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[]") || p.isMappedType(pkgName, goType) {
+		schema, err := p.parseSchemaObject(pkgPath, pkgName, name, goType)
+		if err != nil {
+			return p.wrapErrorf(err, "error.parser.can-not-parse-gotype", "parseParamComment", goType)
+		}
+		operation.RequestBody.Content[types.ContentTypeJSON] = &types.MediaTypeObject{
+			Schema: *schema,
+		}
+	} else {
+		typeName, err := p.registerType(pkgPath, pkgName, matches[3])
+		if err != nil {
+			return err
+		}
+		if types.IsBasicGoType(typeName) {
+			operation.RequestBody.Content[types.ContentTypeJSON] = &types.MediaTypeObject{
+				Schema: types.SchemaObject{
+					Type: "string",
+				},
+			}
+		} else {
+			operation.RequestBody.Content[types.ContentTypeJSON] = &types.MediaTypeObject{
+				Schema: types.SchemaObject{
+					Ref: schemaRef(typeName),
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) handleParam(
+	name string,
+	in string,
+	operation *types.OperationObject,
+	description string,
+	goType string,
+	required bool,
+	pkgPath string,
+	pkgName string) error {
+	parameterObject := types.ParameterObject{
+		Name:        name,
+		In:          in,
+		Description: description,
+		Required:    required,
+	}
+	if in == types.InPath {
+		parameterObject.Required = true
+	}
+	if strings.HasPrefix(goType, "[]") || p.isMappedType(pkgName, goType) {
+		var err error
+		parameterObject.Schema, err = p.parseSchemaObject(pkgPath, pkgName, name, goType)
+		if err != nil {
+			return p.wrapErrorf(err, "error.parser.can-not-parse-gotype", "handleParam", goType)
+		}
+		operation.Parameters = append(operation.Parameters, parameterObject)
+	} else if types.IsGoTypeOASType(goType) {
+		parameterObject.Schema = types.GoTypeOASSchema(goType)
+		operation.Parameters = append(operation.Parameters, parameterObject)
+	} else {
+		typeObj, err := p.lookupType(pkgName, goType)
+		if err != nil {
+			return err
+		}
+		var schemaObject *types.SchemaObject
+		if typeObj == nil {
+			// the parameter is referenced to a placeholder schema in best effort mode, rather than dropped
+			schemaObject, err = p.unresolvedType(pkgName, goType)
+		} else if _, ok := typeObj.Type().Underlying().(*gotypes.Basic); ok {
+			// named types over basic types, such as enums, are referenced by their component schema
+			schemaObject, err = p.parseSchemaObject(pkgPath, pkgName, name, goType)
+		} else {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if schemaObject.ID != "" {
+			parameterObject.Schema = &types.SchemaObject{Ref: util.AddSchemaRefLinkPrefix(schemaObject.ID)}
+			operation.Parameters = append(operation.Parameters, parameterObject)
+		}
+	}
+	return nil
+}
+
+func (p *parser) handleFileOrForm(
+	name,
+	in string,
+	operation *types.OperationObject,
+	goType,
+	description string,
+	required bool,
+	pkgName string) bool {
+	if in == types.InFile || in == types.InFiles || in == types.InForm {
+		if operation.RequestBody == nil {
+			operation.RequestBody = &types.RequestBodyObject{
+				Content: map[string]*types.MediaTypeObject{
+					types.ContentTypeForm: {
+						Schema: types.SchemaObject{
+							Type:       types.TypeObject,
+							Properties: types.NewOrderedMap(),
+						},
+					},
+				},
+				Required: required,
+			}
+		}
+		if in == types.InFile {
+			operation.RequestBody.Content[types.ContentTypeForm].Schema.Properties.Set(name, &types.SchemaObject{
+				Type:        "string",
+				Format:      "binary",
+				Description: description,
+			})
+		} else if in == types.InFiles {
+			operation.RequestBody.Content[types.ContentTypeForm].Schema.Properties.Set(name, &types.SchemaObject{
+				Type: types.TypeArray,
+				Items: &types.SchemaObject{
+					Type:   "string",
+					Format: "binary",
+				},
+				Description: description,
+			})
+		} else if schema, ok := p.getTypeMapping(pkgName, goType); ok {
+			schema.Description = description
+			operation.RequestBody.Content[types.ContentTypeForm].Schema.Properties.Set(name, schema)
+		} else if types.IsGoTypeOASType(goType) {
+			schema := types.GoTypeOASSchema(goType)
+			schema.Description = description
+			operation.RequestBody.Content[types.ContentTypeForm].Schema.Properties.Set(name, schema)
+		}
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseResponseHeader(pkgPath, pkgName string, operation *types.OperationObject, comment string) error {
+	// {status} {name} {jsonType} {goType} {description}
+	// 201  x-next  object  string  "A link"
+	minValidSegments := 4
+	re := regexp.MustCompile(`(?P<status>[\w-]+)[\s]*(?P<name>[\w-]+)[\s]*(?P<jsonType>[\w{}]+)?[\s]+(?P<goType>[\w\-./\[\]]+)?[^"]*(?P<description>.*)?`)
+	matches := re.FindStringSubmatch(comment)
+
+	paramsMap := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if i > 0 && i <= len(matches) {
+			paramsMap[name] = matches[i]
+		}
+	}
+
+	if len(matches) <= minValidSegments {
+		return p.Errorf("error.parser.invalid-comment", "response header", comment, matches)
+	}
+
+	status := paramsMap["status"]
+	if strings.EqualFold(status, "default") {
+		_, err := strconv.Atoi(status)
+		if err != nil {
+			return p.Errorf("error.parser.unexpected-type", "parseResponseHeader", "http status", "int", status)
+		}
+	}
+
+	var responseObject *types.ResponseObject
+	if _, ok := operation.Responses[status]; !ok {
+		responseObject = &types.ResponseObject{
+			Content: map[string]*types.MediaTypeObject{},
+			Headers: make(map[string]*types.HeaderObject),
+		}
+	} else {
+		responseObject = operation.Responses[status]
+	}
+
+	if responseObject.Headers == nil {
+		responseObject.Headers = make(map[string]*types.HeaderObject)
+	}
+
+	if goTypeRaw := paramsMap["goType"]; goTypeRaw != "" {
+		re = regexp.MustCompile(`\[\w*]`)
+		goType := re.ReplaceAllString(goTypeRaw, "[]")
+		if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[]") || p.isMappedType(pkgName, goType) {
+			schema, err := p.parseSchemaObject(pkgPath, pkgName, "", goType)
+			if err != nil {
+				return p.wrapErrorf(err, "error.parser.can-not-parse-gotype", "parseResponseHeader", goType)
+			}
+			responseObject.Headers[paramsMap["name"]] = &types.HeaderObject{
+				Description: strings.Trim(paramsMap["description"], "\""),
+				Schema:      schema,
+			}
+		} else {
+			typeName, err := p.registerType(pkgPath, pkgName, matches[3])
+			if err != nil {
+				return err
+			}
+			if types.IsBasicGoType(typeName) {
+				responseObject.Headers[paramsMap["name"]] = &types.HeaderObject{
+					Description: strings.Trim(paramsMap["description"], "\""),
+					Schema: &types.SchemaObject{
+						Type: "string",
+					},
+				}
+			} else {
+				responseObject.Headers[paramsMap["name"]] = &types.HeaderObject{
+					Description: strings.Trim(paramsMap["description"], "\""),
+					Schema: &types.SchemaObject{
+						Ref: schemaRef(typeName),
+					},
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) parseResponseComment(pkgPath, pkgName string, operation *types.OperationObject, comment string) error {
+	// {status}  {jsonType}  {goType}     {description}
+	// 201       object      models.User  "User Model"
+	// if 204 or something else without empty return payload
+	// 204 "User Model"
+	minValidSegments := 2
+	re := regexp.MustCompile(`(?P<status>[\w]+)[\s]*(?P<jsonType>[\w{}]+)?[\s]+(?P<goType>[\w\-./\[\]]+)?[^"]*(?P<description>.*)?`)
+	matches := re.FindStringSubmatch(comment)
+
+	paramsMap := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if i > 0 && i <= len(matches) {
+			paramsMap[name] = matches[i]
+		}
+	}
+
+	if len(matches) <= minValidSegments {
+		return p.Errorf("error.parser.invalid-comment", "parseResponseComment", comment, matches)
+	}
+
+	status := paramsMap["status"]
+	if !strings.EqualFold(status, "default") {
+		_, err := strconv.Atoi(status)
+		if err != nil {
+			return p.Errorf("error.parser.unexpected-type", "parseResponseComment", "http status", "int", status)
+		}
+	}
+
+	// ignore type if not set
+	if jsonType := paramsMap["jsonType"]; jsonType != "" {
+		switch jsonType {
+		case types.TypeObject, types.TypeArray, "{object}", "{array}":
+		default:
+			return p.Errorf("error.parser.invalid-type", "parseResponseComment", "jsonType", paramsMap["jsonType"])
+		}
+	}
+
+	responseObject := &types.ResponseObject{
+		Content: map[string]*types.MediaTypeObject{},
+	}
+	responseObject.Description = strings.Trim(paramsMap["description"], "\"")
+
+	if goTypeRaw := paramsMap["goType"]; goTypeRaw != "" {
+		re = regexp.MustCompile(`\[\w*]`)
+		goType := re.ReplaceAllString(goTypeRaw, "[]")
+		if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[]") || p.isMappedType(pkgName, goType) {
+			schema, err := p.parseSchemaObject(pkgPath, pkgName, "", goType)
+			if err != nil {
+				return p.wrapErrorf(err, "error.parser.can-not-parse-gotype", "parseResponseComment", goType)
+			}
+			responseObject.Content[types.ContentTypeJSON] = &types.MediaTypeObject{
+				Schema: *schema,
+			}
+		} else {
+			typeName, err := p.registerType(pkgPath, pkgName, matches[3])
+			if err != nil {
+				return err
+			}
+			if types.IsBasicGoType(typeName) {
+				responseObject.Content[types.ContentTypeText] = &types.MediaTypeObject{
+					Schema: types.SchemaObject{
+						Type: "string",
+					},
+				}
+			} else {
+				responseObject.Content[types.ContentTypeJSON] = &types.MediaTypeObject{
+					Schema: types.SchemaObject{
+						Ref: schemaRef(typeName),
+					},
+				}
+			}
+		}
+	}
+	operation.Responses[status] = responseObject
+
+	return nil
+}
+
+// parseRouteComment adds the operation to the path of a route, and returns the path and the method of the route
+func (p *parser) parseRouteComment(operation *types.OperationObject, comment string) (string, string, error) {
+	sourceString := strings.TrimSpace(comment[len(types.AttributeRouter):])
+	validSegments := 3
+
+	// /path [method]
+	//goland:noinspection ALL
+	re := regexp.MustCompile(`([\w./\-{}]+)[^\[]+\[([^\]]+)`)
+	matches := re.FindStringSubmatch(sourceString)
+	if len(matches) != validSegments {
+		return "", "", p.Errorf("error.parser.skip-invalid-comment", types.AttributeRouter, comment)
+	}
+
+	_, ok := p.OpenAPI.Paths[matches[1]]
+	if !ok {
+		p.OpenAPI.Paths[matches[1]] = &types.PathItemObject{}
+		p.ParsedPaths = append(p.ParsedPaths, matches[1])
+	}
+	p.OpenAPI.Paths[matches[1]].SetOperation(matches[2], operation)
+
+	return matches[1], strings.ToLower(matches[2]), nil
+}
+
+// parseWebhookComment adds the operation to a webhook, and returns the name and the method of the webhook
+func (p *parser) parseWebhookComment(operation *types.OperationObject, comment string) (string, string, error) {
+	sourceString := strings.TrimSpace(comment[len(types.AttributeWebhook):])
+	validSegments := 3
+
+	// {name} [method]
+	re := regexp.MustCompile(`([\w.\-]+)[^\[]+\[([^\]]+)`)
+	matches := re.FindStringSubmatch(sourceString)
+	if len(matches) != validSegments {
+		return "", "", p.Errorf("error.parser.skip-invalid-comment", types.AttributeWebhook, comment)
+	}
+
+	if p.OpenAPI.Webhooks == nil {
+		p.OpenAPI.Webhooks = make(types.PathsObject)
+	}
+	if _, ok := p.OpenAPI.Webhooks[matches[1]]; !ok {
+		p.OpenAPI.Webhooks[matches[1]] = &types.PathItemObject{}
+		p.ParsedWebhooks = append(p.ParsedWebhooks, matches[1])
+	}
+	p.OpenAPI.Webhooks[matches[1]].SetOperation(matches[2], operation)
+
+	return matches[1], strings.ToLower(matches[2]), nil
+}
+
+// requireOpenAPI31 reports whether an attribute only OpenAPI 3.1 can document is used in a 3.1 document,
+// a warning located at pos is collected if not
+func (p *parser) requireOpenAPI31(pos token.Pos, attribute string) bool {
+	if p.OpenAPIVersion == util.OpenAPIVersion31 {
+		return true
+	}
+	p.warnf(pos, "warning.parser.requires-openapi-31", attribute)
+	return false
+}
+
+func (p *parser) registerType(pkgPath, pkgName, typeName string) (string, error) {
+	var registerTypeName string
+
+	if types.IsBasicGoType(typeName) {
+		registerTypeName = typeName
+	} else {
+		// already parsed types are returned as is
+		schemaObject, err := p.parseSchemaObject(pkgPath, pkgName, "", typeName)
+		if err != nil {
+			return "", err
+		}
+		registerTypeName = schemaObject.ID
+	}
+	return registerTypeName, nil
+}
+
+func (p *parser) parseSchemaObject(pkgPath, pkgName, fieldName, typeName string) (*types.SchemaObject, error) {
+	schemaObject := &types.SchemaObject{}
+	var err error
+
+	// handler basic and some specific typeName
+	if strings.HasPrefix(typeName, "[]") {
+		itemTypeName := typeName[2:]
+		// encoding/json serialises byte slices as base64 encoded strings
+		if types.IsGoTypeByte(itemTypeName) {
+			schemaObject.Type = types.TypeString
+			schemaObject.Format = types.FormatByte
+			return schemaObject, nil
+		}
+		schemaObject.Type = types.TypeArray
+		schemaObject.Items, err = p.parseSchemaObject(pkgPath, pkgName, fieldName, itemTypeName)
+		if err != nil {
+			return nil, err
+		}
+		if schemaObject.Items.ID != "" {
+			schemaObject.Items = &types.SchemaObject{Ref: util.AddSchemaRefLinkPrefix(schemaObject.Items.ID)}
+		}
+		return schemaObject, nil
+	} else if strings.HasPrefix(typeName, "map[]") {
+		schemaObject.Type = types.TypeObject
+		itemTypeName := typeName[5:]
+		schema, ok := p.getKnownSchema(pkgName, itemTypeName)
+		if ok {
+			schemaObject.Items = &types.SchemaObject{Ref: util.AddSchemaRefLinkPrefix(schema.ID)}
+			return schemaObject, nil
+		}
+		schemaProperty, err := p.parseSchemaObject(pkgPath, pkgName, fieldName, itemTypeName)
+		if err != nil {
+			return nil, err
+		}
+		schemaObject.Properties = types.NewOrderedMap()
+		if fieldName == "" {
+			fieldName = types.DefaultFieldName
+		}
+		schemaObject.Properties.Set(fieldName, schemaProperty)
+		return schemaObject, nil
+	} else if schema, ok := p.getTypeMapping(pkgName, typeName); ok {
+		return schema, nil
+	} else if strings.HasPrefix(typeName, "interface{}") {
+		return schemaObject, nil
+	} else if types.IsGoTypeOASType(typeName) {
+		return types.GoTypeOASSchema(typeName), nil
+	}
+
+	// handler other type
+	if typeName == types.GoTypeIgnored {
+		return schemaObject, nil
+	}
+	typeObj, err := p.lookupType(pkgName, typeName)
+	if err != nil {
+		return nil, err
+	}
+	if typeObj == nil {
+		return p.unresolvedType(pkgName, typeName)
+	}
+	// predeclared types, like any, have no package and hold any value, as interface{} does
+	if typeObj.Pkg() == nil {
+		return schemaObject, nil
+	}
+	if id, ok := p.TypeSchemaIDs[typeObj]; ok {
+		return p.KnownIDSchema[id], nil
+	}
+	typeSpec, exist := p.TypeDecls[typeObj]
+	if !exist {
+		return p.unresolvedType(typePkgPath(typeObj, pkgName), typeName)
+	}
+	pkgName = typeObj.Pkg().Path()
+	pkgPath = p.getPkgDir(pkgName)
+
+	schemaObject.PkgName = pkgName
+	schemaObject.ID = p.registerSchemaID(typeObj)
+	p.KnownIDSchema[schemaObject.ID] = schemaObject
+	if typeSpec.Doc != nil {
+		p.parseSchemaComments(typeSpec.Doc.List, p.KnownIDSchema[schemaObject.ID])
+	}
+
+	// types marshalled by their own methods are not serialised as declared, they are strings unless a @Schema says otherwise
+	if schemaObject.Type == "" && isMarshaler(typeObj) {
+		schemaObject.Type = types.TypeString
+	}
+
+	if schemaObject.Type == "" {
+		switch t := typeSpec.Type.(type) {
+		case *ast.Ident, *ast.SelectorExpr:
+			p.handleBasicType(schemaObject, typeObj)
+		case *ast.StructType:
+			if err := p.handleStructType(schemaObject, t, pkgPath, pkgName); err != nil {
+				return nil, err
+			}
+		case *ast.ArrayType:
+			if err := p.handleArrayType(schemaObject, t, pkgPath, pkgName); err != nil {
+				return nil, err
+			}
+		case *ast.MapType:
+			if err := p.handleMapType(fieldName, schemaObject, t, pkgPath, pkgName); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// register schema object in spec tree if it doesn't exist
+	registerTypeName := schemaObject.ID
+	_, ok := p.OpenAPI.Components.Schemas[util.ReplaceBackslash(registerTypeName)]
+	if !ok {
+		p.OpenAPI.Components.Schemas[util.ReplaceBackslash(registerTypeName)] = schemaObject
+	}
+
+	return schemaObject, nil
+}
+
+// unresolvedType fails on a type whose declaration cannot be found. In best effort mode the type is documented as an
+// empty component schema marked with x-goas-unresolved instead, so that references to it resolve, and a warning
+// located at the annotation or struct field being parsed is collected.
+func (p *parser) unresolvedType(pkgName, typeName string) (*types.SchemaObject, error) {
+	if !p.BestEffort {
+		return nil, p.Errorf("error.parser.missing-definition", typeName, pkgName)
+	}
+	p.warnf(p.Pos, "warning.parser.unresolved-type", typeName, pkgName)
+
+	// the names reserved for the types being parsed are taken too, their schemas are registered once they are parsed
+	id := util.ReplaceBackslash(typeName)
+	for i := 2; ; i++ {
+		schemaObject, taken := p.OpenAPI.Components.Schemas[id]
+		if taken && schemaObject.Unresolved == typeName {
+			return schemaObject, nil
+		}
+		if _, reserved := p.SchemaIDTypes[id]; !taken && !reserved {
+			break
+		}
+		id = fmt.Sprintf("%s%d", util.ReplaceBackslash(typeName), i)
+	}
+	schemaObject := &types.SchemaObject{ID: id, Unresolved: typeName}
+	p.OpenAPI.Components.Schemas[id] = schemaObject
+	return schemaObject, nil
+}
+
+// AddTypeMappings overrides the schema of types, keyed by their import path and name
+func (p *parser) AddTypeMappings(mappings map[string]types.TypeMapping) {
+	for goType, mapping := range mappings {
+		p.TypeMappings[goType] = mapping
+	}
+}
+
+// getTypeMapping returns the schema typeName is mapped to, if any
+func (p *parser) getTypeMapping(pkgName, typeName string) (*types.SchemaObject, bool) {
+	if types.IsBasicGoType(typeName) ||
+		strings.HasPrefix(typeName, "[]") || strings.HasPrefix(typeName, "map[]") || strings.HasPrefix(typeName, "interface{}") {
+		return nil, false
+	}
+	goType := typeName
+	if typeObj, err := p.lookupType(pkgName, typeName); err == nil && typeObj != nil && typeObj.Pkg() != nil {
+		goType = qualifiedTypeName(typeObj)
+	}
+	mapping, ok := p.TypeMappings[goType]
+	if !ok {
+		return nil, false
+	}
+	return mapping.Schema(), true
+}
+
+// isMappedType reports whether typeName is documented by a type mapping
+func (p *parser) isMappedType(pkgName, typeName string) bool {
+	_, ok := p.getTypeMapping(pkgName, typeName)
+	return ok
+}
+
+// registerSchemaID names the component schema of a type. The preferred name for the naming strategy
+// is used, unless another type or the placeholder of an unresolved type already took it, in which case
+// the type is given a more qualified name.
+// The name is provisional, nameSchemas settles which type gets which name once all of them are registered.
+func (p *parser) registerSchemaID(typeObj *gotypes.TypeName) string {
+	if id, ok := p.TypeSchemaIDs[typeObj]; ok {
+		return id
+	}
+	id := schemaID(util.GenSchemaObjectIDs(typePkgPath(typeObj, ""), typeObj.Name(), p.SchemaNaming), func(id string) bool {
+		_, taken := p.SchemaIDTypes[id]
+		return taken || p.isUnresolvedSchema(id)
+	})
+	p.SchemaIDTypes[id] = typeObj
+	p.TypeSchemaIDs[typeObj] = id
+	return id
+}
+
+// isUnresolvedSchema reports whether id names the placeholder of an unresolved type
+func (p *parser) isUnresolvedSchema(id string) bool {
+	schemaObject, ok := p.OpenAPI.Components.Schemas[id]
+	return ok && schemaObject.Unresolved != ""
+}
+
+// schemaID returns the first of the candidate names that is not taken
+func schemaID(candidates []string, taken func(id string) bool) string {
+	for _, candidate := range candidates {
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+	// types declared in funcs may share their fully qualified name
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s%d", candidates[len(candidates)-1], i)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// nameSchemas renames the component schemas of the registered types in the order of their import paths, rather than
+// in the order they were found in, so that the type given a contested name does not depend on which handler or field
+// referenced it first. References to the schemas are renamed along with them.
+func (p *parser) nameSchemas() {
+	typeObjs := make([]*gotypes.TypeName, 0, len(p.TypeSchemaIDs))
+	for typeObj := range p.TypeSchemaIDs {
+		typeObjs = append(typeObjs, typeObj)
+	}
+	sort.Slice(typeObjs, func(i, j int) bool {
+		if name, other := qualifiedTypeName(typeObjs[i]), qualifiedTypeName(typeObjs[j]); name != other {
+			return name < other
+		}
+		return typeObjs[i].Pos() < typeObjs[j].Pos()
+	})
+
+	schemaIDTypes := map[string]*gotypes.TypeName{}
+	renamed := map[string]string{}
+	for _, typeObj := range typeObjs {
+		candidates := util.GenSchemaObjectIDs(typePkgPath(typeObj, ""), typeObj.Name(), p.SchemaNaming)
+		id := schemaID(candidates, func(id string) bool {
+			if _, taken := schemaIDTypes[id]; taken {
+				return true
+			}
+			// the placeholders of unresolved types keep their names
+			return p.isUnresolvedSchema(id)
+		})
+		if id != candidates[0] {
+			var takenBy string
+			if takenByType, ok := schemaIDTypes[candidates[0]]; ok {
+				takenBy = qualifiedTypeName(takenByType)
+			} else {
+				takenBy = p.OpenAPI.Components.Schemas[candidates[0]].Unresolved
+			}
+			p.warnf(typeObj.Pos(), "warning.parser.schema-renamed", qualifiedTypeName(typeObj), id, candidates[0], takenBy)
+		}
+		schemaIDTypes[id] = typeObj
+		if previous := p.TypeSchemaIDs[typeObj]; previous != id {
+			renamed[previous] = id
+		}
+		p.TypeSchemaIDs[typeObj] = id
+	}
+	p.SchemaIDTypes = schemaIDTypes
+	if len(renamed) == 0 {
+		return
+	}
+
+	rename := func(schemas map[string]*types.SchemaObject) map[string]*types.SchemaObject {
+		renamedSchemas := make(map[string]*types.SchemaObject, len(schemas))
+		for id, schemaObject := range schemas {
+			if renamedID, ok := renamed[id]; ok {
+				id = renamedID
+			}
+			renamedSchemas[id] = schemaObject
+		}
+		return renamedSchemas
+	}
+	p.OpenAPI.Components.Schemas = rename(p.OpenAPI.Components.Schemas)
+	p.KnownIDSchema = rename(p.KnownIDSchema)
+	p.OpenAPI.WalkSchemas(func(schemaObject *types.SchemaObject) {
+		if renamedID, ok := renamed[schemaObject.ID]; ok {
+			schemaObject.ID = renamedID
+		}
+		if id, ok := strings.CutPrefix(schemaObject.Ref, util.AddSchemaRefLinkPrefix("")); ok {
+			if renamedID, ok := renamed[id]; ok {
+				schemaObject.Ref = util.AddSchemaRefLinkPrefix(renamedID)
+			}
+		}
+	})
+}
+
+// typePkgPath returns the import path of the package of a type, or pkgName for the predeclared types
+func typePkgPath(typeObj *gotypes.TypeName, pkgName string) string {
+	if typeObj.Pkg() == nil {
+		return pkgName
+	}
+	return typeObj.Pkg().Path()
+}
+
+// qualifiedTypeName returns the import path qualified name of a type, its name alone for the predeclared types
+func qualifiedTypeName(typeObj *gotypes.TypeName) string {
+	if typeObj.Pkg() == nil {
+		return typeObj.Name()
+	}
+	return typeObj.Pkg().Path() + "." + typeObj.Name()
+}
+
+// schemaRef links to the component schema id, types documented without one, such as any, are not linked
+func schemaRef(id string) string {
+	if id == "" {
+		return ""
+	}
+	return util.AddSchemaRefLinkPrefix(id)
+}
+
+// getKnownSchema returns the schema of typeName if it has already been parsed
+func (p *parser) getKnownSchema(pkgName, typeName string) (*types.SchemaObject, bool) {
+	typeObj, err := p.lookupType(pkgName, typeName)
+	if err != nil || typeObj == nil {
+		return nil, false
+	}
+	id, ok := p.TypeSchemaIDs[typeObj]
+	if !ok {
+		return nil, false
+	}
+	return p.KnownIDSchema[id], true
+}
+
+// handleBasicType documents named types over basic types as their underlying type, with the typed constants
+// declared alongside them as the enumeration of their values
+func (p *parser) handleBasicType(schemaObject *types.SchemaObject, typeObj *gotypes.TypeName) {
+	basic, ok := typeObj.Type().Underlying().(*gotypes.Basic)
+	if !ok || !types.IsGoTypeOASType(basic.Name()) {
+		return
+	}
+	basicSchema := types.GoTypeOASSchema(basic.Name())
+	schemaObject.Type = basicSchema.Type
+	schemaObject.Format = basicSchema.Format
+	schemaObject.Minimum = basicSchema.Minimum
+
+	var consts []*gotypes.Const
+	scope := typeObj.Pkg().Scope()
+	for _, name := range scope.Names() {
+		if constObj, ok := scope.Lookup(name).(*gotypes.Const); ok && gotypes.Identical(constObj.Type(), typeObj.Type()) {
+			consts = append(consts, constObj)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	hasDescription := false
+	for _, constObj := range consts {
+		schemaObject.Enum = append(schemaObject.Enum, constantValue(constObj.Val()))
+		schemaObject.EnumVarNames = append(schemaObject.EnumVarNames, constObj.Name())
+
+		description := ""
+		if valueSpec, ok := p.ConstDecls[constObj]; ok {
+			if valueSpec.Doc != nil {
+				description = valueSpec.Doc.Text()
+			} else if valueSpec.Comment != nil {
+				description = valueSpec.Comment.Text()
+			}
+		}
+		description = strings.Join(strings.Fields(description), " ")
+		hasDescription = hasDescription || description != ""
+		schemaObject.EnumDescriptions = append(schemaObject.EnumDescriptions, description)
+	}
+	if !hasDescription {
+		schemaObject.EnumDescriptions = nil
+	}
+}
+
+// isMarshaler reports whether a type, or a pointer to it, implements json.Marshaler or encoding.TextMarshaler
+func isMarshaler(typeObj *gotypes.TypeName) bool {
+	pointer := gotypes.NewPointer(typeObj.Type())
+	for _, method := range []string{"MarshalJSON", "MarshalText"} {
+		obj, _, _ := gotypes.LookupFieldOrMethod(pointer, false, typeObj.Pkg(), method)
+		fn, ok := obj.(*gotypes.Func)
+		if !ok {
+			continue
+		}
+		signature := fn.Type().(*gotypes.Signature)
+		if signature.Params().Len() == 0 && signature.Results().Len() == 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// constantValue converts a constant to the Go value it is marshalled from
+func constantValue(value constant.Value) interface{} {
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value)
+	case constant.Bool:
+		return constant.BoolVal(value)
+	case constant.Int:
+		if i, exact := constant.Int64Val(value); exact {
+			return i
+		}
+		if u, exact := constant.Uint64Val(value); exact {
+			return u
+		}
+	case constant.Float:
+		f, _ := constant.Float64Val(value)
+		return f
+	}
+	return value.String()
+}
+
+func (p *parser) handleStructType(schemaObject *types.SchemaObject, t *ast.StructType, pkgPath, pkgName string) error {
+	schemaObject.Type = types.TypeObject
+	if t.Fields != nil {
+		if err := p.parseSchemaPropertiesFromStructFields(pkgPath, pkgName, schemaObject, t.Fields.List); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) handleArrayType(schemaObject *types.SchemaObject, t *ast.ArrayType, pkgPath, pkgName string) error {
+	schemaObject.Type = types.TypeArray
+	items, isAnonymous, err := p.parseAnonymousType(pkgPath, pkgName, t.Elt)
+	if err != nil {
+		return p.wrapErrorf(err, "error.parser.could-not-parse-type", "array", "struct")
+	}
+	if isAnonymous {
+		schemaObject.Items = items
+		return nil
+	}
+	schemaObject.Items = &types.SchemaObject{}
+	typeAsString := p.getTypeAsString(pkgName, t.Elt)
+	if types.IsGoTypeByte(typeAsString) && t.Len == nil {
+		schemaObject.Type = types.TypeString
+		schemaObject.Format = types.FormatByte
+		schemaObject.Items = nil
+		return nil
+	}
+	typeAsString = strings.TrimLeft(typeAsString, "*")
+	if schema, ok := p.getTypeMapping(pkgName, typeAsString); ok {
+		schemaObject.Items = schema
+	} else if !types.IsBasicGoType(typeAsString) {
+		schemaItemsSchemaObjectID, err := p.registerType(pkgPath, pkgName, typeAsString)
+		if err != nil {
+			return p.wrapErrorf(err, "error.parser.could-not-register-type", "array")
+		}
+		// types holding any value, such as interface{} and any, have no component schema
+		if schemaItemsSchemaObjectID != "" {
+			schemaObject.Items.Ref = util.AddSchemaRefLinkPrefix(schemaItemsSchemaObjectID)
+		}
+	} else if types.IsGoTypeOASType(typeAsString) {
+		schemaObject.Items = types.GoTypeOASSchema(typeAsString)
+	}
+	return nil
+}
+
+func (p *parser) handleMapType(fieldName string, schemaObject *types.SchemaObject, t *ast.MapType, pkgPath, pkgName string) error {
+	schemaObject.Type = types.TypeObject
+	schemaObject.Properties = types.NewOrderedMap()
+	if fieldName == "" {
+		fieldName = types.DefaultFieldName
+	}
+	propertySchema, isAnonymous, err := p.parseAnonymousType(pkgPath, pkgName, t.Value)
+	if err != nil {
+		return p.wrapErrorf(err, "error.parser.could-not-parse-type", "map", "struct")
+	}
+	schemaObject.Properties.Set(fieldName, propertySchema)
+	if isAnonymous {
+		return nil
+	}
+	propertySchema = &types.SchemaObject{}
+	schemaObject.Properties.Set(fieldName, propertySchema)
+	typeAsString := p.getTypeAsString(pkgName, t.Value)
+	typeAsString = strings.TrimLeft(typeAsString, "*")
+	if schema, ok := p.getTypeMapping(pkgName, typeAsString); ok {
+		schemaObject.Properties.Set(fieldName, schema)
+	} else if !types.IsBasicGoType(typeAsString) {
+		schemaItemsSchemaObjectID, err := p.registerType(pkgPath, pkgName, typeAsString)
+		if err != nil {
+			return p.wrapErrorf(err, "error.parser.could-not-register-type", "map")
+		}
+		if schemaItemsSchemaObjectID != "" {
+			propertySchema.Ref = util.AddSchemaRefLinkPrefix(schemaItemsSchemaObjectID)
+		}
+	} else if types.IsGoTypeOASType(typeAsString) {
+		schemaObject.Properties.Set(fieldName, types.GoTypeOASSchema(typeAsString))
+	}
+	return nil
+}
+
+// structField is a property collected from a struct, with what's needed to resolve conflicting names
+type structField struct {
+	name     string
+	schema   *types.SchemaObject
+	required bool
+	tagged   bool
+	depth    int
+}
+
+func (p *parser) parseSchemaPropertiesFromStructFields(
+	pkgPath,
+	pkgName string,
+	structSchema *types.SchemaObject,
+	astFields []*ast.Field) error {
+	if astFields == nil {
+		return nil
+	}
+	structSchema.Properties = types.NewOrderedMap()
+	if structSchema.DisabledFieldNames == nil {
+		structSchema.DisabledFieldNames = map[string]struct{}{}
+	}
+
+	fields, err := p.collectStructFields(pkgPath, pkgName, structSchema, astFields, 0, map[*gotypes.TypeName]bool{})
+	if err != nil {
+		return err
+	}
+	for _, field := range dominantStructFields(fields) {
+		structSchema.Properties.Set(field.name, field.schema)
+		if field.required {
+			structSchema.Required = append(structSchema.Required, field.name)
+		}
+	}
+
+	return nil
+}
+
+// collectStructFields lists the fields of a struct, in order, with the fields of embedded structs promoted in their place
+func (p *parser) collectStructFields(
+	pkgPath,
+	pkgName string,
+	structSchema *types.SchemaObject,
+	astFields []*ast.Field,
+	depth int,
+	visited map[*gotypes.TypeName]bool) ([]*structField, error) {
+	var fields []*structField
+	for _, astField := range astFields {
+		if len(astField.Names) != 0 {
+			for _, astName := range astField.Names {
+				field, err := p.parseStructField(pkgPath, pkgName, structSchema, astField, astName.Name, depth)
+				if err != nil {
+					return nil, err
+				}
+				if field != nil {
+					fields = append(fields, field)
+				}
+			}
+			continue
+		}
+
+		embeddedFields, err := p.collectEmbeddedFields(pkgPath, pkgName, structSchema, astField, depth, visited)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, embeddedFields...)
+	}
+	return fields, nil
+}
+
+// collectEmbeddedFields promotes the fields of an embedded struct, unless the field is named by its json tag.
+// Embedded types that are not structs are treated as a field named after the type, as encoding/json does.
+func (p *parser) collectEmbeddedFields(
+	pkgPath,
+	pkgName string,
+	structSchema *types.SchemaObject,
+	astField *ast.Field,
+	depth int,
+	visited map[*gotypes.TypeName]bool) ([]*structField, error) {
+	astFieldType := astField.Type
+	if astStarExpr, ok := astFieldType.(*ast.StarExpr); ok {
+		astFieldType = astStarExpr.X
+	}
+	var typeIdent *ast.Ident
+	switch t := astFieldType.(type) {
+	case *ast.Ident:
+		typeIdent = t
+	case *ast.SelectorExpr:
+		typeIdent = t.Sel
+	default:
+		return nil, nil
+	}
+
+	if astField.Tag != nil {
+		astFieldTag := reflect.StructTag(strings.Trim(astField.Tag.Value, "`"))
+		if jsonName := strings.Split(astFieldTag.Get("json"), ",")[0]; jsonName != "" && jsonName != "-" {
+			field, err := p.parseStructField(pkgPath, pkgName, structSchema, astField, typeIdent.Name, depth)
+			if err != nil || field == nil {
+				return nil, err
+			}
+			return []*structField{field}, nil
+		}
+	}
+
+	typeName := p.getTypeAsString(pkgName, astFieldType)
+	typeObj, err := p.lookupType(pkgName, typeName)
+	if err != nil {
+		return nil, p.wrapErrorf(err, "error.parser.could-not-register-type", typeName)
+	}
+	var astStructType *ast.StructType
+	if typeObj != nil {
+		if typeSpec, ok := p.TypeDecls[typeObj]; ok {
+			astStructType, _ = typeSpec.Type.(*ast.StructType)
+		}
+	}
+
+	if astStructType == nil {
+		if !typeIdent.IsExported() {
+			return nil, nil
+		}
+		field, err := p.parseStructField(pkgPath, pkgName, structSchema, astField, typeIdent.Name, depth)
+		if err != nil || field == nil {
+			return nil, err
+		}
+		return []*structField{field}, nil
+	}
+
+	if astField.Tag != nil {
+		astFieldTag := reflect.StructTag(strings.Trim(astField.Tag.Value, "`"))
+		if strings.Split(astFieldTag.Get("json"), ",")[0] == "-" {
+			return nil, nil
+		}
+	}
+
+	if p.EmbeddedAllOf && depth == 0 {
+		embeddedSchemaObjectID, err := p.registerType(pkgPath, pkgName, typeName)
+		if err != nil {
+			return nil, p.wrapErrorf(err, "error.parser.could-not-register-type", typeName)
+		}
+		structSchema.AllOf = append(structSchema.AllOf, &types.SchemaObject{
+			Ref: util.AddSchemaRefLinkPrefix(embeddedSchemaObjectID),
+		})
+		return nil, nil
+	}
+
+	if visited[typeObj] || astStructType.Fields == nil {
+		return nil, nil
+	}
+	visited[typeObj] = true
+	defer delete(visited, typeObj)
+
+	embeddedPkgName := typePkgPath(typeObj, pkgName)
+	return p.collectStructFields(p.getPkgDir(embeddedPkgName), embeddedPkgName, structSchema, astStructType.Fields.List, depth+1, visited)
+}
+
+// dominantStructFields drops the fields hidden by another field of the same name, following encoding/json:
+// the least nested field wins, then the field named by a json tag. Fields that still conflict are all dropped.
+func dominantStructFields(fields []*structField) []*structField {
+	byName := map[string][]*structField{}
+	for _, field := range fields {
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	var dominant []*structField
+	for _, field := range fields {
+		conflicts := byName[field.name]
+		if len(conflicts) == 1 {
+			dominant = append(dominant, field)
+			continue
+		}
+		var winner *structField
+		ambiguous := false
+		for _, candidate := range conflicts {
+			switch {
+			case winner == nil || candidate.depth < winner.depth:
+				winner, ambiguous = candidate, false
+			case candidate.depth == winner.depth && candidate.tagged != winner.tagged:
+				if candidate.tagged {
+					winner, ambiguous = candidate, false
+				}
+			case candidate.depth == winner.depth:
+				ambiguous = true
+			}
+		}
+		if winner == field && !ambiguous {
+			dominant = append(dominant, field)
+		}
+	}
+	return dominant
+}
+
+// parseFieldType parses the schema of a struct field's type
+func (p *parser) parseFieldType(pkgPath, pkgName, typeAsString string) (*types.SchemaObject, error) {
+	var err error
+	fieldSchema := &types.SchemaObject{}
+	if strings.HasPrefix(typeAsString, "[]") {
+		fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, "", typeAsString)
+		if err != nil {
+			return nil, p.wrapErrorf(err, "error.parser.could-not-parse-type", "array", typeAsString)
+		}
+	} else if strings.HasPrefix(typeAsString, "map[]") {
+		fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, "", typeAsString)
+		if err != nil {
+			return nil, p.wrapErrorf(err, "error.parser.could-not-parse-type", "map", typeAsString)
+		}
+	} else if schema, ok := p.getTypeMapping(pkgName, typeAsString); ok {
+		fieldSchema = schema
+	} else if strings.HasPrefix(typeAsString, "interface{}") {
+		fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, "", typeAsString)
+		if err != nil {
+			return nil, p.wrapErrorf(err, "error.parser.could-not-parse-type", "interface{}", typeAsString)
+		}
+	} else if !types.IsBasicGoType(typeAsString) {
+		fieldSchemaSchemeObjectID, err := p.registerType(pkgPath, pkgName, typeAsString)
+		if err != nil {
+			return nil, p.wrapErrorf(err, "error.parser.could-not-register-type", typeAsString)
+		}
+		// types holding any value, such as any, have no component schema
+		if fieldSchemaSchemeObjectID == "" {
+			return fieldSchema, nil
+		}
+		fieldSchema.ID = fieldSchemaSchemeObjectID
+		schema, ok := p.KnownIDSchema[fieldSchemaSchemeObjectID]
+		if ok {
+			fieldSchema.Type = schema.Type
+			if schema.Items != nil {
+				fieldSchema.Items = schema.Items
+			}
+		}
+		fieldSchema.Ref = util.AddSchemaRefLinkPrefix(fieldSchemaSchemeObjectID)
+		if fieldSchema.Type != "" {
+			fieldSchema.Type = ""
+		}
+	} else if types.IsGoTypeOASType(typeAsString) {
+		fieldSchema = types.GoTypeOASSchema(typeAsString)
+	}
+	return fieldSchema, nil
+}
+
+// parseStructField parses a single struct field, nil is returned for fields that are not serialized
+func (p *parser) parseStructField(
+	pkgPath,
+	pkgName string,
+	structSchema *types.SchemaObject,
+	astField *ast.Field,
+	name string,
+	depth int) (*structField, error) {
+	pos := p.Pos
+	p.Pos = astField.Pos()
+	defer func() {
+		p.Pos = pos
+	}()
+
+	fieldSchema, isAnonymous, err := p.parseAnonymousType(pkgPath, pkgName, astField.Type)
+	if err != nil {
+		return nil, p.locate(astField.Pos(), p.wrapErrorf(err, "error.parser.could-not-parse-type", "struct", name))
+	}
+	if !isAnonymous {
+		fieldSchema, err = p.parseFieldType(pkgPath, pkgName, strings.TrimLeft(p.getTypeAsString(pkgName, astField.Type), "*"))
+		if err != nil {
+			return nil, p.locate(astField.Pos(), err)
+		}
+	}
+
+	fieldSchema.FieldName = name
+	if p.Nullable && isNullableType(astField.Type) {
+		setNullable(fieldSchema)
+	}
+	field := &structField{
+		name:   name,
+		schema: fieldSchema,
+		depth:  depth,
+	}
+	if _, disabled := structSchema.DisabledFieldNames[name]; disabled && depth == 0 {
+		return nil, nil
+	}
+	if astField.Tag == nil {
+		return field, nil
+	}
+
+	astFieldTag := reflect.StructTag(strings.Trim(astField.Tag.Value, "`"))
+	tagText := ""
+
+	if tag := astFieldTag.Get("goas"); tag != "" {
+		tagText = tag
+	}
+	tagValues := strings.Split(tagText, ",")
+	for _, v := range tagValues {
+		if v == "-" {
+			if depth == 0 {
+				structSchema.DisabledFieldNames[name] = struct{}{}
+			}
+			fieldSchema.Deprecated = true
+			return nil, nil
+		}
+	}
+
+	if tag := astFieldTag.Get("json"); tag != "" {
+		tagText = tag
+	}
+	tagValues = strings.Split(tagText, ",")
+	for _, v := range tagValues {
+		if v == "-" {
+			if depth == 0 {
+				structSchema.DisabledFieldNames[field.name] = struct{}{}
+			}
+			fieldSchema.Deprecated = true
+			return nil, nil
+		} else if v == types.KeywordRequired {
+			field.required = true
+		} else if v != "" && v != types.KeywordRequired && v != "omitempty" {
+			field.name = v
+			field.tagged = true
+		}
+	}
+
+	// required fields are only added to the struct once conflicts between embedded fields are resolved
+	fieldRequired := &types.SchemaObject{}
+	if err := p.parseFieldTags(pkgPath, pkgName, field.name, astFieldTag, fieldRequired, fieldSchema, field.required); err != nil {
+		return nil, p.locate(astField.Tag.Pos(), err)
+	}
+	field.required = len(fieldRequired.Required) > 0
+	return field, nil
+}
+
+// isNullableType reports whether encoding/json marshals the nil value of a type expression as null
+func isNullableType(astExpr ast.Expr) bool {
+	switch t := astExpr.(type) {
+	case *ast.StarExpr, *ast.MapType:
+		return true
+	case *ast.ArrayType:
+		return t.Len == nil
+	}
+	return false
+}
+
+// setNullable makes a schema nullable. As the siblings of a $ref are ignored, references are wrapped in an allOf.
+func setNullable(schema *types.SchemaObject) {
+	refAllOf(schema)
+	schema.Nullable = true
+}
+
+// refAllOf moves the $ref of a schema to allOf, as the keywords next to a $ref are ignored before OpenAPI 3.1
+func refAllOf(schema *types.SchemaObject) {
+	if schema.Ref != "" {
+		schema.AllOf = []*types.SchemaObject{{Ref: schema.Ref}}
+		schema.Ref = ""
+	}
+}
+
+func (p *parser) parseFieldTags(
+	pkgPath,
+	pkgName,
+	name string,
+	astFieldTag reflect.StructTag,
+	structSchema,
+	fieldSchema *types.SchemaObject,
+	isRequired bool) error {
+	if err := p.handleExample(astFieldTag, fieldSchema); err != nil {
+		return err
+	}
+
+	if _, ok := astFieldTag.Lookup("required"); ok || isRequired {
+		structSchema.Required = append(structSchema.Required, name)
+	}
+
+	if desc := astFieldTag.Get("description"); desc != "" {
+		fieldSchema.Description = desc
+	}
+
+	if err := p.handleMultipleOf(astFieldTag, fieldSchema); err != nil {
+		return err
+	}
+
+	if err := p.handleRange(astFieldTag, fieldSchema); err != nil {
+		return err
+	}
+
+	if pattern := astFieldTag.Get("pattern"); pattern != "" {
+		fieldSchema.Pattern = pattern
+	}
+
+	p.handleLengthMinMax(astFieldTag, fieldSchema)
+
+	if fieldSchema.Type == types.TypeArray {
+		p.handleItemMinMax(astFieldTag, fieldSchema)
+
+		if uniqueItems := astFieldTag.Get("uniqueItems"); uniqueItems != "" {
+			fieldSchema.UniqueItems, _ = strconv.ParseBool(uniqueItems)
+		}
+	}
+
+	if fieldSchema.Type == types.TypeObject {
+		p.handlePropertyMinMax(astFieldTag, fieldSchema)
+	}
+
+	if err := p.handleEnumTag(astFieldTag, fieldSchema); err != nil {
+		return err
+	}
+
+	if err := p.handleConstTag(astFieldTag, fieldSchema); err != nil {
+		return err
+	}
+
+	if err := p.handleAllOfTag(pkgPath, pkgName, astFieldTag, fieldSchema); err != nil {
+		return err
+	}
+
+	if err := p.handleOneOfTag(pkgPath, pkgName, astFieldTag, fieldSchema); err != nil {
+		return err
+	}
+
+	if err := p.handleAnyOfTag(pkgPath, pkgName, astFieldTag, fieldSchema); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *parser) handleExample(astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) error {
+	if tag := astFieldTag.Get("example"); tag != "" {
+		switch fieldSchema.Type {
+		case types.TypeBoolean:
+			fieldSchema.Example, _ = strconv.ParseBool(tag)
+		case types.TypeInteger:
+			fieldSchema.Example, _ = strconv.Atoi(tag)
+		case types.TypeNumber:
+			fieldSchema.Example, _ = strconv.ParseFloat(tag, 64)
+		case types.TypeArray:
+			b, err := json.RawMessage(tag).MarshalJSON()
+			if err != nil {
+				fieldSchema.Example = types.MessageInvalidExample
+			} else {
+				var sliceOfInterface []interface{}
+				err := json.Unmarshal(b, &sliceOfInterface)
+				if err != nil {
+					fieldSchema.Example = types.MessageInvalidExample
+				} else {
+					fieldSchema.Example = sliceOfInterface
+				}
+			}
+		case types.TypeObject:
+			b, err := json.RawMessage(tag).MarshalJSON()
+			if err != nil {
+				fieldSchema.Example = types.MessageInvalidExample
+			} else {
+				mapOfInterface := map[string]interface{}{}
+				err := json.Unmarshal(b, &mapOfInterface)
+				if err != nil {
+					fieldSchema.Example = types.MessageInvalidExample
+				} else {
+					fieldSchema.Example = mapOfInterface
+				}
+			}
+		default:
+			fieldSchema.Example = tag
+		}
+
+		// the siblings of a $ref are ignored before OpenAPI 3.1
+		if fieldSchema.Example != nil && p.OpenAPIVersion != util.OpenAPIVersion31 {
+			refAllOf(fieldSchema)
+		}
+	}
+	return nil
+}
+
+func (p *parser) handleMultipleOf(astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) error {
+	if multipleOf := astFieldTag.Get("multipleOf"); multipleOf != "" {
+		switch fieldSchema.Type {
+		case types.TypeInteger:
+			fieldSchema.MultipleOf, _ = strconv.Atoi(multipleOf)
+		case types.TypeNumber:
+			fieldSchema.MultipleOf, _ = strconv.ParseFloat(multipleOf, 64)
+		default:
+			return p.Errorf("error.parser.unable-to-parse-value", "multipleOf", "multipleOf", multipleOf)
+		}
+	}
+	return nil
+}
+
+func (p *parser) handleRange(astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) error {
+	if min := astFieldTag.Get("minimum"); min != "" {
+		switch fieldSchema.Type {
+		case types.TypeInteger:
+			fieldSchema.Minimum, _ = strconv.Atoi(min)
+		case types.TypeNumber:
+			fieldSchema.Minimum, _ = strconv.ParseFloat(min, 64)
+		default:
+			return p.Errorf("error.parser.unable-to-parse-value", "range", "minimum", min)
+		}
+	}
+
+	if max := astFieldTag.Get("maximum"); max != "" {
+		switch fieldSchema.Type {
+		case types.TypeInteger:
+			fieldSchema.Maximum, _ = strconv.Atoi(max)
+		case types.TypeNumber:
+			fieldSchema.Maximum, _ = strconv.ParseFloat(max, 64)
+		default:
+			return p.Errorf("error.parser.unable-to-parse-value", "range", "maximum", max)
+		}
+	}
+
+	if exclusiveMin, _ := strconv.ParseBool(astFieldTag.Get("exclusiveMinimum")); exclusiveMin {
+		fieldSchema.ExclusiveMinimum = true
+	}
+
+	if exclusiveMax, _ := strconv.ParseBool(astFieldTag.Get("exclusiveMaximum")); exclusiveMax {
+		fieldSchema.ExclusiveMaximum = true
+	}
+
+	return nil
+}
+
+func (p *parser) handleLengthMinMax(astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) {
+	if minLength := astFieldTag.Get("minLength"); minLength != "" {
+		fieldSchema.MinLength, _ = strconv.Atoi(minLength)
+	}
+
+	if maxLength := astFieldTag.Get("maxLength"); maxLength != "" {
+		fieldSchema.MaxLength, _ = strconv.Atoi(maxLength)
+	}
+}
+
+func (p *parser) handleItemMinMax(astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) {
+	if minItems := astFieldTag.Get("minItems"); minItems != "" {
+		fieldSchema.MinItems, _ = strconv.Atoi(minItems)
+	}
+
+	if maxItems := astFieldTag.Get("maxItems"); maxItems != "" {
+		fieldSchema.MaxItems, _ = strconv.Atoi(maxItems)
+	}
+}
+
+func (p *parser) handlePropertyMinMax(astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) {
+	if minProperties := astFieldTag.Get("minProperties"); minProperties != "" {
+		fieldSchema.MinProperties, _ = strconv.Atoi(minProperties)
+	}
+
+	if maxProperties := astFieldTag.Get("maxProperties"); maxProperties != "" {
+		fieldSchema.MaxProperties, _ = strconv.Atoi(maxProperties)
+	}
+}
+
+func (p *parser) handleEnumTag(astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) error {
+	if enum := astFieldTag.Get("enum"); enum != "" {
+		values, err := util.SplitEnumValues(enum)
+		if err != nil {
+			return p.Errorf("error.parser.unable-to-parse-value", "enum", "enum", err)
+		}
+		oasType := fieldSchema.Type
+		if oasType == types.TypeArray && fieldSchema.Items != nil {
+			oasType = fieldSchema.Items.Type
+		}
+		fieldSchema.Enum = nil
+		for _, value := range values {
+			enumValue, err := p.parseEnumValue(oasType, value)
+			if err != nil {
+				return err
+			}
+			fieldSchema.Enum = append(fieldSchema.Enum, enumValue)
+		}
+	}
+	return nil
+}
+
+// handleConstTag documents the only value a field may have, as const in OpenAPI 3.1 and as an enum of one value before
+func (p *parser) handleConstTag(astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) error {
+	value, ok := astFieldTag.Lookup("const")
+	if !ok {
+		return nil
+	}
+	constValue, err := p.parseEnumValue(fieldSchema.Type, value)
+	if err != nil {
+		return err
+	}
+	if p.OpenAPIVersion == util.OpenAPIVersion31 {
+		fieldSchema.Const = constValue
+	} else {
+		fieldSchema.Enum = []interface{}{constValue}
+	}
+	return nil
+}
+
+// parseEnumValue converts an enum value to the type of the schema it enumerates
+func (p *parser) parseEnumValue(oasType, value string) (interface{}, error) {
+	var enumValue interface{}
+	var err error
+	switch oasType {
+	case types.TypeInteger:
+		enumValue, err = strconv.ParseInt(value, 10, 64)
+	case types.TypeNumber:
+		enumValue, err = strconv.ParseFloat(value, 64)
+	case types.TypeBoolean:
+		enumValue, err = strconv.ParseBool(value)
+	default:
+		enumValue = value
+	}
+	if err != nil {
+		return nil, p.Errorf("error.parser.invalid-enum-value", value, oasType)
+	}
+	return enumValue, nil
+}
+
+func (p *parser) handleAllOfTag(pkgPath, pkgName string, astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) error {
+	if allOf := astFieldTag.Get("allOf"); allOf != "" {
+		typeNames := strings.Split(strings.TrimSpace(allOf), ",")
+		for _, typeName := range typeNames {
+			schemaObject, err := p.parseSchemaObject(pkgPath, pkgName, "", typeName)
+			if err != nil {
+				return p.wrapErrorf(err, "error.parser.missing-object-with-name", "allOf", typeName)
+			}
+			fieldSchema.AllOf = append(fieldSchema.AllOf, &types.SchemaObject{
+				Ref: util.AddSchemaRefLinkPrefix(schemaObject.ID),
+			})
+		}
+	}
+	return nil
+}
+
+func (p *parser) handleOneOfTag(pkgPath, pkgName string, astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) error {
+	if oneOf := astFieldTag.Get("oneOf"); oneOf != "" {
+		// get discriminator if available
+		if discriminator := astFieldTag.Get("discriminator"); discriminator != "" {
+			fieldSchema.Discriminator = &types.Discriminator{PropertyName: discriminator}
+		}
+
+		typeNames := strings.Split(strings.TrimSpace(oneOf), ",")
+		for _, typeName := range typeNames {
+			schemaObject, err := p.parseSchemaObject(pkgPath, pkgName, "", typeName)
+			if err != nil {
+				return p.wrapErrorf(err, "error.parser.missing-object-with-name", "oneOf", typeName)
+			}
+
+			if fieldSchema.Discriminator != nil && schemaObject.Properties != nil {
+				if _, ok := schemaObject.Properties.Get(fieldSchema.Discriminator.PropertyName); !ok {
+					return p.Errorf("error.parser.missing-discriminator-field", "oneOf", fieldSchema.Discriminator.PropertyName, schemaObject.ID)
+				}
+			}
+
+			fieldSchema.OneOf = append(fieldSchema.OneOf, &types.SchemaObject{
+				Ref: util.AddSchemaRefLinkPrefix(schemaObject.ID),
+			})
+		}
+	}
+	return nil
+}
+
+func (p *parser) handleAnyOfTag(pkgPath, pkgName string, astFieldTag reflect.StructTag, fieldSchema *types.SchemaObject) error {
+	if anyOf := astFieldTag.Get("anyOf"); anyOf != "" {
+		typeNames := strings.Split(strings.TrimSpace(anyOf), ",")
+		for _, typeName := range typeNames {
+			schemaObject, err := p.parseSchemaObject(pkgPath, pkgName, "", typeName)
+			if err != nil {
+				return p.wrapErrorf(err, "error.parser.missing-object-with-name", "anyOf", typeName)
+			}
+			fieldSchema.AnyOf = append(fieldSchema.AnyOf, &types.SchemaObject{
+				Ref: util.AddSchemaRefLinkPrefix(schemaObject.ID),
+			})
+		}
+	}
+	return nil
+}
+
+// parseAnonymousType builds inline schemas for type expressions of anonymous structs, including pointers, arrays
+// and maps of them, as they have no name to be registered and referenced by. isAnonymous is false for any other type.
+func (p *parser) parseAnonymousType(pkgPath, pkgName string, astExpr ast.Expr) (schemaObject *types.SchemaObject, isAnonymous bool, err error) {
+	switch t := astExpr.(type) {
+	case *ast.StarExpr:
+		return p.parseAnonymousType(pkgPath, pkgName, t.X)
+	case *ast.StructType:
+		schemaObject = &types.SchemaObject{}
+		return schemaObject, true, p.handleStructType(schemaObject, t, pkgPath, pkgName)
+	case *ast.ArrayType:
+		items, isAnonymous, err := p.parseAnonymousType(pkgPath, pkgName, t.Elt)
+		if !isAnonymous || err != nil {
+			return nil, isAnonymous, err
+		}
+		return &types.SchemaObject{
+			Type:  types.TypeArray,
+			Items: items,
+		}, true, nil
+	case *ast.MapType:
+		value, isAnonymous, err := p.parseAnonymousType(pkgPath, pkgName, t.Value)
+		if !isAnonymous || err != nil {
+			return nil, isAnonymous, err
+		}
+		return &types.SchemaObject{
+			Type:       types.TypeObject,
+			Properties: types.NewOrderedMap().Set(types.DefaultFieldName, value),
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+func (p *parser) getTypeAsString(pkgName string, fieldType interface{}) string {
+	astArrayType, ok := fieldType.(*ast.ArrayType)
+	if ok {
+		return fmt.Sprintf("[]%v", p.getTypeAsString(pkgName, astArrayType.Elt))
+	}
+
+	astMapType, ok := fieldType.(*ast.MapType)
+	if ok {
+		return fmt.Sprintf("map[]%v", p.getTypeAsString(pkgName, astMapType.Value))
+	}
+
+	_, ok = fieldType.(*ast.InterfaceType)
+	if ok {
+		return "interface{}"
+	}
+
+	astStarExpr, ok := fieldType.(*ast.StarExpr)
+	if ok {
+		return fmt.Sprintf("%v", p.getTypeAsString(pkgName, astStarExpr.X))
+	}
+
+	if astExpr, ok := fieldType.(ast.Expr); ok {
+		if typeName := p.getQualifiedTypeName(pkgName, astExpr); typeName != "" {
+			return typeName
+		}
+	}
+
+	astSelectorExpr, ok := fieldType.(*ast.SelectorExpr)
+	if ok {
+		packageNameIdent, _ := astSelectorExpr.X.(*ast.Ident)
+		return packageNameIdent.Name + "." + astSelectorExpr.Sel.Name
+	}
+
+	return fmt.Sprint(fieldType)
+}
+
+// getQualifiedTypeName returns the import path qualified name of the package level type that
+// the type checker bound the expression to, so it resolves without guessing
+func (p *parser) getQualifiedTypeName(pkgName string, astExpr ast.Expr) string {
+	loaded, ok := p.Packages[pkgName]
+	if !ok || loaded.TypesInfo == nil {
+		return ""
+	}
+	typeAndValue, ok := loaded.TypesInfo.Types[astExpr]
+	if !ok {
+		return ""
+	}
+	// aliases are named as written, as long as they are declared at package level
+	if alias, ok := typeAndValue.Type.(*gotypes.Alias); ok {
+		if typeObj := alias.Obj(); typeObj.Pkg() != nil && typeObj.Parent() == typeObj.Pkg().Scope() {
+			return qualifiedTypeName(typeObj)
+		}
+	}
+	named, ok := gotypes.Unalias(typeAndValue.Type).(*gotypes.Named)
+	if !ok {
+		return ""
+	}
+	typeObj := named.Obj()
+	if typeObj.Pkg() == nil || typeObj.Parent() != typeObj.Pkg().Scope() {
+		return ""
+	}
+	return qualifiedTypeName(typeObj)
+}
+
+// lookupType resolves a goType, as written in a comment or tag of pkgName, to its declaration.
+// Names are resolved in the scope of the handler func or package the way the compiler would: through the
+// imports of the package, or in the package itself, which may also qualify its types with its own name.
+// Types may also be qualified by the import path of their package, or the end of it.
+// Nil is returned if there is no such type, and an error if the name matches more than one type.
+func (p *parser) lookupType(pkgName, typeName string) (*gotypes.TypeName, error) {
+	scope := p.getScope(pkgName)
+
+	i := strings.LastIndex(typeName, ".")
+	if i < 0 {
+		if scope == nil {
+			scope = gotypes.Universe
+		}
+		_, obj := scope.LookupParent(typeName, token.NoPos)
+		typeObj, _ := obj.(*gotypes.TypeName)
+		return typeObj, nil
+	}
+
+	qualifier, name := typeName[:i], typeName[i+1:]
+	if strings.Contains(qualifier, "/") {
+		return p.findType(typeName, name, qualifier)
+	}
+
+	imported := p.getImportedPkgs(pkgName, qualifier)
+	switch len(imported) {
+	case 0:
+		if loaded, ok := p.Packages[pkgName]; ok && loaded.Types != nil && loaded.Name == qualifier {
+			typeObj, _ := loaded.Types.Scope().Lookup(name).(*gotypes.TypeName)
+			return typeObj, nil
+		}
+		return nil, nil
+	case 1:
+		typeObj, _ := imported[0].Scope().Lookup(name).(*gotypes.TypeName)
+		return typeObj, nil
+	default:
+		var paths []string
+		for _, importedPkg := range imported {
+			paths = append(paths, importedPkg.Path())
+		}
+		return nil, p.Errorf("error.parser.ambiguous-type", "lookupType", typeName, strings.Join(paths, ", "))
+	}
+}
+
+// getScope returns the innermost scope goTypes in pkgName are resolved in
+func (p *parser) getScope(pkgName string) *gotypes.Scope {
+	loaded, ok := p.Packages[pkgName]
+	if !ok || loaded.Types == nil {
+		return nil
+	}
+	for scope := p.OperationScope; scope != nil; scope = scope.Parent() {
+		if scope == loaded.Types.Scope() {
+			return p.OperationScope
+		}
+	}
+	return loaded.Types.Scope()
+}
+
+// getImportedPkgs returns the packages imported as qualifier by pkgName. Outside of a handler
+// func all of the package's files are considered, which may yield more than one package.
+func (p *parser) getImportedPkgs(pkgName, qualifier string) []*gotypes.Package {
+	scope := p.getScope(pkgName)
+	if scope == nil {
+		return nil
+	}
+	if scope != p.Packages[pkgName].Types.Scope() {
+		if _, obj := scope.LookupParent(qualifier, token.NoPos); obj != nil {
+			if pkgNameObj, ok := obj.(*gotypes.PkgName); ok {
+				return []*gotypes.Package{pkgNameObj.Imported()}
+			}
+		}
+		return nil
+	}
+
+	var imported []*gotypes.Package
+	seen := map[*gotypes.Package]bool{}
+	for i := 0; i < scope.NumChildren(); i++ {
+		if pkgNameObj, ok := scope.Child(i).Lookup(qualifier).(*gotypes.PkgName); ok && !seen[pkgNameObj.Imported()] {
+			seen[pkgNameObj.Imported()] = true
+			imported = append(imported, pkgNameObj.Imported())
+		}
+	}
+	return imported
+}
+
+// findType looks a type named name up in the package imported as pkgPath, or else in the packages whose import path
+// ends with it, which must then be a single package
+func (p *parser) findType(typeName, name, pkgPath string) (*gotypes.TypeName, error) {
+	if loaded, ok := p.Packages[pkgPath]; ok && loaded.Types != nil {
+		typeObj, _ := loaded.Types.Scope().Lookup(name).(*gotypes.TypeName)
+		return typeObj, nil
+	}
+
+	var candidates []*gotypes.TypeName
+	for _, pkgName := range p.getSortedPkgNames() {
+		loaded := p.Packages[pkgName]
+		if loaded.Types == nil || !strings.HasSuffix(loaded.PkgPath, "/"+pkgPath) {
+			continue
+		}
+		if typeObj, ok := loaded.Types.Scope().Lookup(name).(*gotypes.TypeName); ok {
+			candidates = append(candidates, typeObj)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return candidates[0], nil
+	default:
+		var paths []string
+		for _, typeObj := range candidates {
+			paths = append(paths, typeObj.Pkg().Path())
+		}
+		return nil, p.Errorf("error.parser.ambiguous-type", "lookupType", typeName, strings.Join(paths, ", "))
+	}
+}
+
+func (p *parser) getSortedPkgNames() []string {
+	pkgNames := make([]string, 0, len(p.Packages))
+	for pkgName := range p.Packages {
+		pkgNames = append(pkgNames, pkgName)
+	}
+	sort.Strings(pkgNames)
+	return pkgNames
+}
+
+func (p *parser) getPkgDir(pkgName string) string {
+	loaded, ok := p.Packages[pkgName]
+	if !ok || len(loaded.GoFiles) == 0 {
+		return ""
+	}
+	return filepath.Dir(loaded.GoFiles[0])
+}
+
+func (p *parser) validateOperationID(id string) error {
+	for _, oid := range p.KnownOperationIDs {
+		if oid == id {
+			return p.Errorf("error.parser.discrete-id-in-use", fmt.Sprintf("operationID %s", id))
+		}
+	}
+
+	p.KnownOperationIDs = append(p.KnownOperationIDs, id)
+	return nil
+}
@@ -1,18 +1,20 @@
-package main
+package goas
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"go/ast"
+	goparser "go/parser"
 	"os"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/leonelquinteros/gotext"
 
-	"github.com/iancoleman/orderedmap"
-
 	"gopkg.in/yaml.v2"
 
 	"github.com/deanstalker/goas/pkg/types"
@@ -37,7 +39,7 @@ func TestParseParamComment(t *testing.T) {
 	}{
 		"string param in path": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `locale   path   string   true   "Locale code"`,
 			wantOp: &types.OperationObject{
 				Parameters: []types.ParameterObject{
@@ -48,8 +50,7 @@ func TestParseParamComment(t *testing.T) {
 						Required:    true,
 						Example:     nil,
 						Schema: &types.SchemaObject{
-							Type:   "string",
-							Format: "string",
+							Type: "string",
 						},
 					},
 				},
@@ -59,7 +60,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"string param in path without desc": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `locale   path   string   true`,
 			wantOp: &types.OperationObject{
 				Parameters: []types.ParameterObject{
@@ -70,8 +71,7 @@ func TestParseParamComment(t *testing.T) {
 						Required:    true,
 						Example:     nil,
 						Schema: &types.SchemaObject{
-							Type:   "string",
-							Format: "string",
+							Type: "string",
 						},
 					},
 				},
@@ -81,7 +81,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"string in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `firstname   body   string   true   "First Name"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -100,7 +100,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"[]string in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `address   body   []string   true   "Address"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -122,7 +122,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"map[]string in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `address   body   map[]string   true   "Address"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -145,7 +145,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"timestamp in path": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `time   path   time.Time   true   "Timestamp"`,
 			wantOp: &types.OperationObject{
 				Parameters: []types.ParameterObject{
@@ -167,7 +167,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"file in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `image file ignored true "Image upload"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -192,7 +192,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"files in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `image files string true "Image upload"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -219,7 +219,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"form field with string in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comment: `content form string false "Content field"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -230,7 +230,6 @@ func TestParseParamComment(t *testing.T) {
 								Properties: types.NewOrderedMap().
 									Set("content", &types.SchemaObject{
 										Type:        "string",
-										Format:      "string",
 										Description: "Content field",
 									}),
 							},
@@ -246,7 +245,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"struct in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: fmt.Sprintf("%s/pkg/types", pkgName),
 			comment: `externaldocs body ExternalDocumentationObject false "External Documentation"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -280,7 +279,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"array of structs in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: fmt.Sprintf("%s/pkg/types", pkgName),
 			comment: `externaldocs body []ExternalDocumentationObject false "External Documentation"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -317,7 +316,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"map of structs in body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: fmt.Sprintf("%s/pkg/types", pkgName),
 			comment: `externaldocs body map[]ExternalDocumentationObject false "External Documentation"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -368,7 +367,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"struct in alternate package - test oneOf a kind": {
 			pkgPath: dir,
-			pkgName: "test",
+			pkgName: unitPkg,
 			comment: `post body unit.FruitOneOfAKind false "Fruit - Test oneOf a Kind"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -414,7 +413,7 @@ func TestParseParamComment(t *testing.T) {
 					Properties: types.NewOrderedMap().
 						Set("kind", &types.SchemaObject{
 							FieldName: "Kind",
-							OneOf: []*types.ReferenceObject{
+							OneOf: []*types.SchemaObject{
 								{
 									Ref: "#/components/schemas/Citrus",
 								},
@@ -430,7 +429,7 @@ func TestParseParamComment(t *testing.T) {
 		// "struct in alternate package - test oneOf a kind - invalid type: {}"
 		"struct in alternate package - test oneOf a kind with discriminator": {
 			pkgPath: dir,
-			pkgName: "test",
+			pkgName: unitPkg,
 			comment: `post body unit.FruitOneOfAKindDisc false "Fruit - Test oneOf a Kind"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -476,7 +475,7 @@ func TestParseParamComment(t *testing.T) {
 					Properties: types.NewOrderedMap().
 						Set("kind", &types.SchemaObject{
 							FieldName: "Kind",
-							OneOf: []*types.ReferenceObject{
+							OneOf: []*types.SchemaObject{
 								{
 									Ref: "#/components/schemas/Citrus",
 								},
@@ -494,7 +493,7 @@ func TestParseParamComment(t *testing.T) {
 		},
 		"struct in alternate package - test oneOf a kind with invalid discriminator": {
 			pkgPath: dir,
-			pkgName: "test",
+			pkgName: unitPkg,
 			comment: `post body unit.FruitOneOfAKindInvalidDisc false "Fruit - Test oneOf a Kind"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -540,7 +539,7 @@ func TestParseParamComment(t *testing.T) {
 					Properties: types.NewOrderedMap().
 						Set("kind", &types.SchemaObject{
 							FieldName: "Kind",
-							OneOf: []*types.ReferenceObject{
+							OneOf: []*types.SchemaObject{
 								{
 									Ref: "#/components/schemas/Citrus",
 								},
@@ -554,11 +553,11 @@ func TestParseParamComment(t *testing.T) {
 						}),
 				},
 			},
-			expectErr: fmt.Errorf("oneOf: unable to find discriminator field: kindle, in schema: Citrus"),
+			expectErr: errors.New("test/unit/combination.go:21:19: oneOf: unable to find discriminator field: kindle, in schema: Citrus"),
 		},
 		"struct in alternate package - test allOf a kind": {
 			pkgPath: dir,
-			pkgName: "test",
+			pkgName: unitPkg,
 			comment: `post body unit.FruitAllOfAKind false "Fruit - Test allOf a Kind"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -604,7 +603,7 @@ func TestParseParamComment(t *testing.T) {
 					Properties: types.NewOrderedMap().
 						Set("kind", &types.SchemaObject{
 							FieldName: "Kind",
-							AllOf: []*types.ReferenceObject{
+							AllOf: []*types.SchemaObject{
 								{
 									Ref: "#/components/schemas/Citrus",
 								},
@@ -620,7 +619,7 @@ func TestParseParamComment(t *testing.T) {
 		// "struct in alternate package - test allOf a kind - invalid type: {}"
 		"struct in alternate package - test anyOf a kind": {
 			pkgPath: dir,
-			pkgName: "test",
+			pkgName: unitPkg,
 			comment: `post body unit.FruitAnyOfAKind false "Fruit - Test anyOf a Kind"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -666,7 +665,7 @@ func TestParseParamComment(t *testing.T) {
 					Properties: types.NewOrderedMap().
 						Set("kind", &types.SchemaObject{
 							FieldName: "Kind",
-							AnyOf: []*types.ReferenceObject{
+							AnyOf: []*types.SchemaObject{
 								{
 									Ref: "#/components/schemas/Citrus",
 								},
@@ -682,7 +681,7 @@ func TestParseParamComment(t *testing.T) {
 		// "struct in alternate package - test anyOf a kind - invalid type: {}"
 		"test enum - string and numeric": {
 			pkgPath: dir,
-			pkgName: "test",
+			pkgName: unitPkg,
 			comment: `post body unit.EnumProperties false "Enum Properties"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -707,7 +706,7 @@ func TestParseParamComment(t *testing.T) {
 						Set("status", &types.SchemaObject{
 							FieldName: "Status",
 							Type:      "string",
-							Enum: []string{
+							Enum: []interface{}{
 								"active",
 								"pending",
 								"disabled",
@@ -716,19 +715,51 @@ func TestParseParamComment(t *testing.T) {
 						Set("error_code", &types.SchemaObject{
 							FieldName: "ErrorCode",
 							Type:      "integer",
-							Enum: []string{
-								"400",
-								"404",
-								"500",
+							Format:    "int64",
+							Enum: []interface{}{
+								int64(400),
+								int64(404),
+								int64(500),
+							},
+						}).
+						Set("ratio", &types.SchemaObject{
+							FieldName: "Ratio",
+							Type:      "number",
+							Format:    "double",
+							Enum:      []interface{}{0.5, 1.5},
+						}).
+						Set("enabled", &types.SchemaObject{
+							FieldName: "Enabled",
+							Type:      "boolean",
+							Enum:      []interface{}{true},
+						}).
+						Set("label", &types.SchemaObject{
+							FieldName: "Label",
+							Type:      "string",
+							Enum:      []interface{}{"red, green", "blue"},
+						}).
+						Set("codes", &types.SchemaObject{
+							FieldName: "Codes",
+							Type:      "array",
+							Items: &types.SchemaObject{
+								Type:   "integer",
+								Format: "int64",
 							},
+							Enum: []interface{}{int64(1), int64(2)},
 						}),
 				},
 			},
 			expectErr: nil,
 		},
+		"test enum - invalid value for type": {
+			pkgPath:   dir,
+			pkgName:   unitPkg,
+			comment:   `post body unit.InvalidEnumProperties false "Invalid Enum Properties"`,
+			expectErr: errors.New("test/unit/object.go:17:18: enum value not-found is not a valid integer"),
+		},
 		"test object - limited properties": {
 			pkgPath: dir,
-			pkgName: "test",
+			pkgName: unitPkg,
 			comment: `post body unit.LimitedObjectProperties false "Limited Object Properties"`,
 			wantOp: &types.OperationObject{
 				RequestBody: &types.RequestBodyObject{
@@ -878,16 +909,19 @@ func TestParseParamComment(t *testing.T) {
 						Set("multiple_of_10", &types.SchemaObject{
 							FieldName:  "MultipleOf10",
 							Type:       "integer",
+							Format:     "int64",
 							MultipleOf: 10,
 						}).
 						Set("multiple_of_5_pc", &types.SchemaObject{
 							FieldName:  "MultipleOf5PC",
 							Type:       "number",
+							Format:     "double",
 							MultipleOf: 0.2,
 						}).
 						Set("range_int", &types.SchemaObject{
 							FieldName:   "RangeInt",
 							Type:        "integer",
+							Format:      "int64",
 							Minimum:     1,
 							Maximum:     100,
 							Example:     3,
@@ -896,6 +930,7 @@ func TestParseParamComment(t *testing.T) {
 						Set("range_float", &types.SchemaObject{
 							FieldName: "RangeFloat",
 							Type:      "number",
+							Format:    "double",
 							Minimum:   0.01,
 							Maximum:   0.5,
 							Example:   0.2,
@@ -1061,6 +1096,64 @@ func TestParseParamComment(t *testing.T) {
 			},
 			expectErr: nil,
 		},
+		"enum param in query": {
+			pkgPath: dir,
+			pkgName: unitPkg,
+			comment: `status   query   unit.Status   false   "Account status"`,
+			wantOp: &types.OperationObject{
+				Parameters: []types.ParameterObject{
+					{
+						Name:        "status",
+						In:          "query",
+						Description: "Account status",
+						Schema: &types.SchemaObject{
+							Ref: "#/components/schemas/Status",
+						},
+					},
+				},
+			},
+			wantSchema: map[string]*types.SchemaObject{
+				"Status": {
+					ID:           "Status",
+					PkgName:      fmt.Sprintf("%s/test/unit", pkgName),
+					Type:         "string",
+					Enum:         []interface{}{"active", "pending", "disabled"},
+					EnumVarNames: []string{"StatusActive", "StatusPending", "StatusDisabled"},
+					EnumDescriptions: []string{
+						"StatusActive accounts can sign in",
+						"StatusPending accounts are awaiting approval",
+						"",
+					},
+				},
+			},
+			expectErr: nil,
+		},
+		"array param in query": {
+			pkgPath: dir,
+			pkgName: unitPkg,
+			comment: `ids   query   []int   false   "Account IDs"`,
+			wantOp: &types.OperationObject{
+				Parameters: []types.ParameterObject{
+					{
+						Name:        "ids",
+						In:          "query",
+						Description: "Account IDs",
+						Schema: &types.SchemaObject{
+							Type:  "array",
+							Items: &types.SchemaObject{Type: "integer", Format: "int64"},
+						},
+					},
+				},
+			},
+			wantSchema: make(map[string]*types.SchemaObject),
+			expectErr:  nil,
+		},
+		"unknown type param in query": {
+			pkgPath:   dir,
+			pkgName:   unitPkg,
+			comment:   `status   query   unit.Unknown   false   "Account status"`,
+			expectErr: errors.New("parseParamComment: unable to handle params: can not find definition of unit.Unknown ast.TypeSpec in package github.com/deanstalker/goas/test/unit"),
+		},
 	}
 
 	for name, tc := range tests {
@@ -1072,7 +1165,7 @@ func TestParseParamComment(t *testing.T) {
 
 			op := &types.OperationObject{}
 			if err := p.parseParamComment(tc.pkgPath, tc.pkgName, op, tc.comment); err != nil {
-				assert.Equal(t, tc.expectErr, err)
+				assert.EqualError(t, err, tc.expectErr.Error())
 				return
 			}
 
@@ -1267,7 +1360,7 @@ func TestParseInfo(t *testing.T) {
 				Description: "This is a test",
 				Version:     "1.0.0",
 			},
-			expectErr: errors.New("info.title cannot be empty"),
+			expectErr: errors.New("./main.go: info.title cannot be empty"),
 		},
 		"missing version": {
 			comments: []string{
@@ -1279,7 +1372,7 @@ func TestParseInfo(t *testing.T) {
 				Description: "This is a test",
 				Version:     "",
 			},
-			expectErr: errors.New("info.version cannot be empty"),
+			expectErr: errors.New("./main.go: info.version cannot be empty"),
 		},
 	}
 
@@ -1293,7 +1386,7 @@ func TestParseInfo(t *testing.T) {
 			fileComments := commentSliceToCommentGroup(tc.comments)
 
 			if err := p.parseInfo(fileComments); err != nil {
-				assert.Equal(t, tc.expectErr, err)
+				assert.EqualError(t, err, tc.expectErr.Error())
 			}
 
 			assert.Equal(t, tc.want, p.OpenAPI.Info)
@@ -1410,7 +1503,7 @@ func TestParseInfoServers(t *testing.T) {
 			fileComments := commentSliceToCommentGroup(tc.comments)
 
 			if err := p.parseInfo(fileComments); err != nil {
-				assert.Equal(t, tc.expectErr, err)
+				assert.EqualError(t, err, tc.expectErr.Error())
 			}
 
 			sort.Slice(p.OpenAPI.Servers, func(i, j int) bool {
@@ -1722,7 +1815,7 @@ func TestParseInfoExternalDoc(t *testing.T) {
 			fileComments := commentSliceToCommentGroup(tc.comments)
 
 			if err := p.parseInfo(fileComments); err != nil {
-				assert.Equal(t, tc.expectErr, err)
+				assert.EqualError(t, err, tc.expectErr.Error())
 			}
 
 			assert.Equal(t, tc.want, p.OpenAPI)
@@ -1880,7 +1973,7 @@ func TestParseInfoTags(t *testing.T) {
 			fileComments := commentSliceToCommentGroup(tc.comments)
 
 			if err := p.parseInfo(fileComments); err != nil {
-				assert.Equal(t, tc.expectErr, err)
+				assert.EqualError(t, err, tc.expectErr.Error())
 			}
 
 			assert.Equal(t, tc.want, p.OpenAPI)
@@ -1900,7 +1993,7 @@ func TestParseOperation(t *testing.T) {
 	}{
 		"hidden operation": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title Super secret endpoint",
 				"// @Description Ssshhh",
@@ -1912,7 +2005,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"get operation without params": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title Get all the things",
 				"// @Description Get all the items",
@@ -1952,7 +2045,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"get operation with params": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title Get all the things",
 				"// @Description Get all the items",
@@ -1988,8 +2081,7 @@ func TestParseOperation(t *testing.T) {
 								Description: "Locale code",
 								Required:    true,
 								Schema: &types.SchemaObject{
-									Type:   "string",
-									Format: "string",
+									Type: "string",
 								},
 							},
 						},
@@ -2000,7 +2092,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"post operation with body": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title Create a user",
 				"// @Description Create a user",
@@ -2037,8 +2129,7 @@ func TestParseOperation(t *testing.T) {
 								Description: "Locale code",
 								Required:    true,
 								Schema: &types.SchemaObject{
-									Type:   "string",
-									Format: "string",
+									Type: "string",
 								},
 							},
 						},
@@ -2061,7 +2152,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"patch operation": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title Update a user",
 				"// @Description Update a user",
@@ -2099,8 +2190,7 @@ func TestParseOperation(t *testing.T) {
 								Description: "Locale code",
 								Required:    true,
 								Schema: &types.SchemaObject{
-									Type:   "string",
-									Format: "string",
+									Type: "string",
 								},
 							},
 							{
@@ -2133,7 +2223,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"put operation": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title Replace a user",
 				"// @Description Replace a user",
@@ -2171,8 +2261,7 @@ func TestParseOperation(t *testing.T) {
 								Description: "Locale code",
 								Required:    true,
 								Schema: &types.SchemaObject{
-									Type:   "string",
-									Format: "string",
+									Type: "string",
 								},
 							},
 							{
@@ -2205,7 +2294,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"delete operation": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title Delete a user",
 				"// @Description Delete a user",
@@ -2242,8 +2331,7 @@ func TestParseOperation(t *testing.T) {
 								Description: "Locale code",
 								Required:    true,
 								Schema: &types.SchemaObject{
-									Type:   "string",
-									Format: "string",
+									Type: "string",
 								},
 							},
 							{
@@ -2264,7 +2352,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"options operation": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title User pre-flight",
 				"// @Description User pre-flight",
@@ -2299,8 +2387,7 @@ func TestParseOperation(t *testing.T) {
 								Description: "Locale code",
 								Required:    true,
 								Schema: &types.SchemaObject{
-									Type:   "string",
-									Format: "string",
+									Type: "string",
 								},
 							},
 							{
@@ -2321,7 +2408,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"head operation": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title User Head Lookup",
 				"// @Description User Head Lookup",
@@ -2345,8 +2432,7 @@ func TestParseOperation(t *testing.T) {
 								Description: "Locale code",
 								Required:    true,
 								Schema: &types.SchemaObject{
-									Type:   "string",
-									Format: "string",
+									Type: "string",
 								},
 							},
 							{
@@ -2367,7 +2453,7 @@ func TestParseOperation(t *testing.T) {
 		},
 		"trace operation without params": {
 			pkgPath: dir,
-			pkgName: "main",
+			pkgName: unitPkg,
 			comments: []string{
 				"// @Title User Trace (should be disabled)",
 				"// @Description User Trace (should be disabled)",
@@ -2391,8 +2477,7 @@ func TestParseOperation(t *testing.T) {
 								Description: "Locale code",
 								Required:    true,
 								Schema: &types.SchemaObject{
-									Type:   "string",
-									Format: "string",
+									Type: "string",
 								},
 							},
 							{
@@ -2423,7 +2508,7 @@ func TestParseOperation(t *testing.T) {
 			fileComments := commentSliceToCommentGroup(tc.comments)
 
 			if err = p.parseOperation(tc.pkgPath, tc.pkgName, fileComments[0].List); err != nil {
-				assert.Equal(t, tc.expectErr, err)
+				assert.EqualError(t, err, tc.expectErr.Error())
 				return
 			}
 
@@ -2432,61 +2517,1061 @@ func TestParseOperation(t *testing.T) {
 	}
 }
 
-func TestIntegration(t *testing.T) {
-	// @see https://github.com/OAI/OpenAPI-Specification/blob/master/examples/v3.0/petstore.yaml
+func TestLookupType(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	modulePath := util.ModulePath("./")
+	pkgName, _ := modulePath.Get()
+	tests := map[string]struct {
+		pkgName     string
+		funcName    string
+		typeName    string
+		wantPkgName string
+		wantErr     error
+	}{
+		"imported package is preferred over a package with the same name": {
+			pkgName:     fmt.Sprintf("%s/test/resolve", pkgName),
+			typeName:    "models.Error",
+			wantPkgName: fmt.Sprintf("%s/test/resolve/internal/models", pkgName),
+		},
+		"import path qualified type": {
+			pkgName:     fmt.Sprintf("%s/test/resolve", pkgName),
+			typeName:    "api/models.Error",
+			wantPkgName: fmt.Sprintf("%s/test/resolve/api/models", pkgName),
+		},
+		"type declared in the handler func": {
+			pkgName:     fmt.Sprintf("%s/test/resolve", pkgName),
+			funcName:    "resolve",
+			typeName:    "Request",
+			wantPkgName: fmt.Sprintf("%s/test/resolve", pkgName),
+		},
+		"type qualified by the name of its own package": {
+			pkgName:     unitPkg,
+			typeName:    "unit.Citrus",
+			wantPkgName: fmt.Sprintf("%s/test/unit", pkgName),
+		},
+		"type in a package that is not imported": {
+			pkgName:  fmt.Sprintf("%s/test/resolve", pkgName),
+			typeName: "unit.Citrus",
+		},
+		"type qualified by a directory of its package": {
+			pkgName:  fmt.Sprintf("%s/test/resolve", pkgName),
+			typeName: "test.Citrus",
+		},
+		"package name of a package that is not imported": {
+			pkgName:  unitPkg,
+			typeName: "models.Error",
+		},
+		"unknown type": {
+			pkgName:  fmt.Sprintf("%s/test/resolve", pkgName),
+			typeName: "models.Unknown",
+		},
+		"type of the package is preferred over a dependency": {
+			pkgName:     unitPkg,
+			typeName:    "Location",
+			wantPkgName: fmt.Sprintf("%s/test/unit", pkgName),
+		},
+		"unqualified type of another package": {
+			pkgName:  fmt.Sprintf("%s/test/resolve", pkgName),
+			typeName: "Citrus",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			if tc.funcName != "" {
+				for _, astFile := range p.Packages[tc.pkgName].Syntax {
+					for _, astDeclaration := range astFile.Decls {
+						if astFuncDeclaration, ok := astDeclaration.(*ast.FuncDecl); ok && astFuncDeclaration.Name.Name == tc.funcName {
+							p.OperationScope = p.Packages[tc.pkgName].TypesInfo.Scopes[astFuncDeclaration.Type]
+						}
+					}
+				}
+			}
+
+			typeObj, err := p.lookupType(tc.pkgName, tc.typeName)
+			if tc.wantErr != nil {
+				assert.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			if tc.wantPkgName == "" {
+				assert.Nil(t, typeObj)
+				return
+			}
+			if assert.NotNil(t, typeObj) {
+				assert.Equal(t, tc.wantPkgName, typeObj.Pkg().Path())
+			}
+		})
+	}
+}
+
+func TestSchemaNaming(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	modulePath := util.ModulePath("./")
+	pkgName, _ := modulePath.Get()
+	tests := map[string]struct {
+		naming       string
+		bestEffort   bool
+		typeNames    []string
+		wantIDs      []string
+		wantWarnings []string
+	}{
+		"short names are qualified on a collision": {
+			naming:    util.SchemaNamingShort,
+			typeNames: []string{"api/models.Error", "internal/models.Error", "api/models.Error"},
+			wantIDs:   []string{"Error", "models.Error", "Error"},
+			wantWarnings: []string{
+				fmt.Sprintf(
+					"%s/test/resolve/internal/models.Error was registered as models.Error, as Error is already used by %s/test/resolve/api/models.Error",
+					pkgName,
+					pkgName,
+				),
+			},
+		},
+		"names do not depend on the order types are found in": {
+			naming:    util.SchemaNamingShort,
+			typeNames: []string{"internal/models.Error", "api/models.Error"},
+			wantIDs:   []string{"models.Error", "Error"},
+			wantWarnings: []string{
+				fmt.Sprintf(
+					"%s/test/resolve/internal/models.Error was registered as models.Error, as Error is already used by %s/test/resolve/api/models.Error",
+					pkgName,
+					pkgName,
+				),
+			},
+		},
+		"qualified names are qualified further on a collision": {
+			naming:    util.SchemaNamingQualified,
+			typeNames: []string{"api/models.Error", "internal/models.Error"},
+			wantIDs:   []string{"models.Error", "internal.models.Error"},
+			wantWarnings: []string{
+				fmt.Sprintf(
+					"%s/test/resolve/internal/models.Error was registered as internal.models.Error, as models.Error is already used by %s/test/resolve/api/models.Error",
+					pkgName,
+					pkgName,
+				),
+			},
+		},
+		"names of unresolved types are not taken": {
+			naming:     util.SchemaNamingShort,
+			bestEffort: true,
+			typeNames:  []string{"Error", "api/models.Error"},
+			wantIDs:    []string{"Error", "models.Error"},
+			wantWarnings: []string{
+				fmt.Sprintf("can not find definition of Error in package %s/test/unit, it is documented as an empty schema", pkgName),
+				fmt.Sprintf(
+					"%s/test/resolve/api/models.Error was registered as models.Error, as Error is already used by Error",
+					pkgName,
+				),
+			},
+		},
+		"full path names never collide": {
+			naming:    util.SchemaNamingFullPath,
+			typeNames: []string{"api/models.Error", "internal/models.Error"},
+			wantIDs: []string{
+				"github.com.deanstalker.goas.test.resolve.api.models.Error",
+				"github.com.deanstalker.goas.test.resolve.internal.models.Error",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			p.SchemaNaming = tc.naming
+			p.BestEffort = tc.bestEffort
+			p.OpenAPI.Paths = types.PathsObject{}
+
+			for i, typeName := range tc.typeNames {
+				assert.NoError(t, p.parseOperation(dir, unitPkg, commentSliceToCommentGroup([]string{
+					fmt.Sprintf(`// @Success 200 {object} %s "Error"`, typeName),
+					fmt.Sprintf("// @Route /errors/%d [get]", i),
+				})[0].List))
+			}
+			p.nameSchemas()
+			for i, wantID := range tc.wantIDs {
+				schema := p.OpenAPI.Paths[fmt.Sprintf("/errors/%d", i)].Get.Responses["200"].Content[types.ContentTypeJSON].Schema
+				assert.Equal(t, util.AddSchemaRefLinkPrefix(wantID), schema.Ref)
+				if assert.Contains(t, p.OpenAPI.Components.Schemas, wantID) {
+					assert.Equal(t, wantID, p.OpenAPI.Components.Schemas[wantID].ID)
+				}
+			}
+			var warnings []string
+			for _, diagnostic := range p.Diagnostics {
+				assert.Equal(t, types.SeverityWarning, diagnostic.Severity)
+				warnings = append(warnings, diagnostic.Message)
+			}
+			assert.Equal(t, tc.wantWarnings, warnings)
+		})
+	}
+}
+
+func TestParseEmbeddedStructFields(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	tests := map[string]struct {
+		typeName      string
+		embeddedAllOf bool
+		wantKeys      []string
+		wantRequired  []string
+		wantAllOf     []*types.SchemaObject
+	}{
+		"embedded struct fields are promoted": {
+			typeName:     "unit.Admin",
+			wantKeys:     []string{"id", "name", "role"},
+			wantRequired: []string{"name"},
+		},
+		"fields of the struct shadow embedded fields": {
+			typeName: "unit.Moderator",
+			wantKeys: []string{"id", "nickname", "name"},
+		},
+		"conflicting embedded fields at the same depth are dropped": {
+			typeName:     "unit.Editor",
+			wantKeys:     []string{"id", "updated_by"},
+			wantRequired: nil,
+		},
+		"embedded structs named by a json tag are not promoted": {
+			typeName: "unit.Owner",
+			wantKeys: []string{"user"},
+		},
+		"embedded structs composed with allOf": {
+			typeName:      "unit.Admin",
+			embeddedAllOf: true,
+			wantKeys:      []string{"role"},
+			wantAllOf: []*types.SchemaObject{
+				{Ref: "#/components/schemas/User"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			p.EmbeddedAllOf = tc.embeddedAllOf
+
+			schema, err := p.parseSchemaObject(dir, unitPkg, "", tc.typeName)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantKeys, schema.Properties.Keys())
+			assert.Equal(t, tc.wantRequired, schema.Required)
+			assert.Equal(t, tc.wantAllOf, schema.AllOf)
+		})
+	}
+}
+
+func TestParseAnonymousStructs(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	modulePath := util.ModulePath("./")
+	pkgName, _ := modulePath.Get()
+	tests := map[string]struct {
+		typeName string
+		want     *types.SchemaObject
+	}{
+		"anonymous struct fields, arrays and maps of anonymous structs": {
+			typeName: "unit.Page",
+			want: &types.SchemaObject{
+				ID:                 "Page",
+				PkgName:            fmt.Sprintf("%s/test/unit", pkgName),
+				Type:               "object",
+				DisabledFieldNames: map[string]struct{}{},
+				Properties: types.NewOrderedMap().
+					Set("meta", &types.SchemaObject{
+						FieldName:          "Meta",
+						Type:               "object",
+						Description:        "Paging metadata",
+						DisabledFieldNames: map[string]struct{}{},
+						Properties: types.NewOrderedMap().
+							Set("page", &types.SchemaObject{
+								FieldName: "Page",
+								Type:      "integer",
+								Format:    "int64",
+								Minimum:   1,
+							}).
+							Set("total", &types.SchemaObject{
+								FieldName: "Total",
+								Type:      "integer",
+								Format:    "int64",
+							}),
+					}).
+					Set("items", &types.SchemaObject{
+						FieldName: "Items",
+						Type:      "array",
+						MaxItems:  100,
+						Items: &types.SchemaObject{
+							Type:               "object",
+							Required:           []string{"id"},
+							DisabledFieldNames: map[string]struct{}{},
+							Properties: types.NewOrderedMap().
+								Set("id", &types.SchemaObject{
+									FieldName: "ID",
+									Type:      "string",
+								}),
+						},
+					}).
+					Set("labels", &types.SchemaObject{
+						FieldName: "Labels",
+						Type:      "object",
+						Properties: types.NewOrderedMap().
+							Set("key", &types.SchemaObject{
+								Type:               "object",
+								DisabledFieldNames: map[string]struct{}{},
+								Properties: types.NewOrderedMap().
+									Set("color", &types.SchemaObject{
+										FieldName: "Color",
+										Type:      "string",
+									}),
+							}),
+					}),
+			},
+		},
+		"array of anonymous structs": {
+			typeName: "unit.Rows",
+			want: &types.SchemaObject{
+				ID:      "Rows",
+				PkgName: fmt.Sprintf("%s/test/unit", pkgName),
+				Type:    "array",
+				Items: &types.SchemaObject{
+					Type:               "object",
+					DisabledFieldNames: map[string]struct{}{},
+					Properties: types.NewOrderedMap().
+						Set("value", &types.SchemaObject{
+							FieldName: "Value",
+							Type:      "string",
+						}),
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			schema, err := p.parseSchemaObject(dir, unitPkg, "", tc.typeName)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, schema)
+		})
+	}
+}
+
+func TestParseNamedBasicTypes(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	modulePath := util.ModulePath("./")
+	pkgName, _ := modulePath.Get()
+	tests := map[string]struct {
+		typeName string
+		want     *types.SchemaObject
+	}{
+		"string with const enum": {
+			typeName: "unit.Status",
+			want: &types.SchemaObject{
+				ID:           "Status",
+				PkgName:      fmt.Sprintf("%s/test/unit", pkgName),
+				Type:         "string",
+				Enum:         []interface{}{"active", "pending", "disabled"},
+				EnumVarNames: []string{"StatusActive", "StatusPending", "StatusDisabled"},
+				EnumDescriptions: []string{
+					"StatusActive accounts can sign in",
+					"StatusPending accounts are awaiting approval",
+					"",
+				},
+			},
+		},
+		"int with iota enum": {
+			typeName: "unit.Priority",
+			want: &types.SchemaObject{
+				ID:           "Priority",
+				PkgName:      fmt.Sprintf("%s/test/unit", pkgName),
+				Type:         "integer",
+				Format:       "int64",
+				Enum:         []interface{}{int64(1), int64(2)},
+				EnumVarNames: []string{"PriorityLow", "PriorityHigh"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			schema, err := p.parseSchemaObject(dir, unitPkg, "", tc.typeName)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, schema)
+		})
+	}
+}
+
+func TestParseMarshalers(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	modulePath := util.ModulePath("./")
+	pkgName, _ := modulePath.Get()
+	tests := map[string]struct {
+		typeName string
+		want     *types.SchemaObject
+	}{
+		"json marshaler is a string": {
+			typeName: "unit.Money",
+			want:     &types.SchemaObject{ID: "Money", Type: "string"},
+		},
+		"json marshaler on pointer with schema comment": {
+			typeName: "unit.Coordinates",
+			want:     &types.SchemaObject{ID: "Coordinates", Type: "array"},
+		},
+		"text marshaler with consts is a string without enum": {
+			typeName: "unit.Level",
+			want:     &types.SchemaObject{ID: "Level", Type: "string"},
+		},
+		"json marshaler with schema comment and format": {
+			typeName: "unit.Timeout",
+			want:     &types.SchemaObject{ID: "Timeout", Type: "integer", Format: "int64"},
+		},
+		"promoted json marshaler": {
+			typeName: "unit.Stamped",
+			want:     &types.SchemaObject{ID: "Stamped", Type: "string"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+
+			tc.want.PkgName = fmt.Sprintf("%s/test/unit", pkgName)
+			schema, err := p.parseSchemaObject(dir, unitPkg, "", tc.typeName)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, schema)
+		})
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+
+	p, err := partialBootstrap()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	schema, err := p.parseSchemaObject(dir, unitPkg, "", "unit.Envelope")
+	if !assert.NoError(t, err) {
+		return
+	}
+	value, ok := schema.Properties.Get("value")
+	assert.True(t, ok)
+	assert.Equal(t, &types.SchemaObject{FieldName: "Value"}, value)
+	values, ok := schema.Properties.Get("values")
+	assert.True(t, ok)
+	assert.Equal(t, &types.SchemaObject{FieldName: "Values", Type: "array", Items: &types.SchemaObject{}}, values)
+}
+
+func TestTypeMappings(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	modulePath := util.ModulePath("./")
+	pkgName, _ := modulePath.Get()
+
+	p, err := partialBootstrap()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	p.AddTypeMappings(map[string]types.TypeMapping{
+		fmt.Sprintf("%s/test/unit.Money", pkgName): {Type: "string", Format: "decimal", Pattern: `^\d+\.\d{2}$`},
+	})
+
+	schema, err := p.parseSchemaObject(dir, unitPkg, "", "unit.Schedule")
+	assert.NoError(t, err)
+	assert.Equal(t, &types.SchemaObject{
+		ID:                 "Schedule",
+		PkgName:            fmt.Sprintf("%s/test/unit", pkgName),
+		Type:               "object",
+		DisabledFieldNames: map[string]struct{}{},
+		Properties: types.NewOrderedMap().
+			Set("every", &types.SchemaObject{
+				FieldName: "Every",
+				Type:      "integer",
+				Format:    "int64",
+			}).
+			Set("starts", &types.SchemaObject{
+				FieldName: "Starts",
+				Type:      "string",
+				Format:    "date-time",
+			}).
+			Set("payload", &types.SchemaObject{
+				FieldName: "Payload",
+			}).
+			Set("price", &types.SchemaObject{
+				FieldName: "Price",
+				Type:      "string",
+				Format:    "decimal",
+				Pattern:   `^\d+\.\d{2}$`,
+			}).
+			Set("skipped", &types.SchemaObject{
+				FieldName: "Skipped",
+				Type:      "array",
+				Items: &types.SchemaObject{
+					Type:   "string",
+					Format: "date-time",
+				},
+			}).
+			Set("timeouts", &types.SchemaObject{
+				FieldName: "Timeouts",
+				Type:      "object",
+				Properties: types.NewOrderedMap().
+					Set("key", &types.SchemaObject{
+						Type:   "integer",
+						Format: "int64",
+					}),
+			}),
+	}, schema)
+	_, registered := p.OpenAPI.Components.Schemas["Money"]
+	assert.False(t, registered)
+
+	op := &types.OperationObject{}
+	assert.NoError(t, p.parseParamComment(dir, unitPkg, op, `since query time.Duration true "Since"`))
+	assert.NoError(t, p.parseParamComment(dir, unitPkg, op, `ttl form time.Duration false "TTL"`))
+	assert.Equal(t, []types.ParameterObject{
+		{
+			Name:        "since",
+			In:          "query",
+			Description: "Since",
+			Required:    true,
+			Schema: &types.SchemaObject{
+				Type:   "integer",
+				Format: "int64",
+			},
+		},
+	}, op.Parameters)
+	ttl, _ := op.RequestBody.Content[types.ContentTypeForm].Schema.Properties.Get("ttl")
+	assert.Equal(t, &types.SchemaObject{
+		Type:        "integer",
+		Format:      "int64",
+		Description: "TTL",
+	}, ttl)
+}
+
+func TestParseBasicTypeFormats(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	modulePath := util.ModulePath("./")
+	pkgName, _ := modulePath.Get()
+
+	p, err := partialBootstrap()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	schema, err := p.parseSchemaObject(dir, unitPkg, "", "unit.Widths")
+	assert.NoError(t, err)
+	assert.Equal(t, &types.SchemaObject{
+		ID:                 "Widths",
+		PkgName:            fmt.Sprintf("%s/test/unit", pkgName),
+		Type:               "object",
+		DisabledFieldNames: map[string]struct{}{},
+		Properties: types.NewOrderedMap().
+			Set("small", &types.SchemaObject{FieldName: "Small", Type: "integer", Format: "int32"}).
+			Set("rune", &types.SchemaObject{FieldName: "Rune", Type: "integer", Format: "int32"}).
+			Set("count", &types.SchemaObject{FieldName: "Count", Type: "integer", Format: "int64", Minimum: 0}).
+			Set("total", &types.SchemaObject{FieldName: "Total", Type: "integer", Format: "int64", Minimum: 1}).
+			Set("ratio", &types.SchemaObject{FieldName: "Ratio", Type: "number", Format: "float"}).
+			Set("data", &types.SchemaObject{FieldName: "Data", Type: "string", Format: "byte"}).
+			Set("blob", &types.SchemaObject{ID: "Blob", FieldName: "Blob", Ref: "#/components/schemas/Blob"}).
+			Set("ids", &types.SchemaObject{
+				FieldName: "Ids",
+				Type:      "array",
+				Items:     &types.SchemaObject{Type: "integer", Format: "int32"},
+			}).
+			Set("checksum", &types.SchemaObject{FieldName: "Checksum", Type: "string", Format: "byte"}),
+	}, schema)
+	assert.Equal(t, &types.SchemaObject{
+		ID:      "Blob",
+		PkgName: fmt.Sprintf("%s/test/unit", pkgName),
+		Type:    "string",
+		Format:  "byte",
+	}, p.OpenAPI.Components.Schemas["Blob"])
+
+	op := &types.OperationObject{}
+	assert.NoError(t, p.parseParamComment(dir, unitPkg, op, `limit query uint16 false "Limit"`))
+	assert.NoError(t, p.parseParamComment(dir, unitPkg, op, `ratio form float32 false "Ratio"`))
+	assert.Equal(t, &types.SchemaObject{Type: "integer", Format: "int64", Minimum: 0}, op.Parameters[0].Schema)
+	ratio, _ := op.RequestBody.Content[types.ContentTypeForm].Schema.Properties.Get("ratio")
+	assert.Equal(t, &types.SchemaObject{Type: "number", Format: "float", Description: "Ratio"}, ratio)
+}
+
+func TestNullable(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	modulePath := util.ModulePath("./")
+	pkgName, _ := modulePath.Get()
+
+	p, err := partialBootstrap()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	p.Nullable = true
+
+	schema, err := p.parseSchemaObject(dir, unitPkg, "", "unit.Profile")
+	assert.NoError(t, err)
+	assert.Equal(t, &types.SchemaObject{
+		ID:                 "Profile",
+		PkgName:            fmt.Sprintf("%s/test/unit", pkgName),
+		Type:               "object",
+		DisabledFieldNames: map[string]struct{}{},
+		Properties: types.NewOrderedMap().
+			Set("name", &types.SchemaObject{FieldName: "Name", Type: "string"}).
+			Set("nickname", &types.SchemaObject{FieldName: "Nickname", Type: "string", Nullable: true}).
+			Set("aliases", &types.SchemaObject{
+				FieldName: "Aliases",
+				Type:      "array",
+				Items:     &types.SchemaObject{Type: "string"},
+				Nullable:  true,
+			}).
+			Set("labels", &types.SchemaObject{
+				FieldName:  "Labels",
+				Type:       "object",
+				Properties: types.NewOrderedMap().Set("key", &types.SchemaObject{Type: "string"}),
+				Nullable:   true,
+			}).
+			Set("manager", &types.SchemaObject{
+				ID:        "Blob",
+				FieldName: "Manager",
+				AllOf:     []*types.SchemaObject{{Ref: "#/components/schemas/Blob"}},
+				Nullable:  true,
+			}).
+			Set("codes", &types.SchemaObject{
+				FieldName: "Codes",
+				Type:      "array",
+				Items:     &types.SchemaObject{Type: "integer", Format: "int32"},
+			}),
+	}, schema)
+
+	types.ConvertToOpenAPI31(&p.OpenAPI)
+	assert.Equal(t, types.OpenAPIVersion31, p.OpenAPI.OpenAPI)
+	b, err := json.Marshal(p.OpenAPI.Components.Schemas["Profile"].Properties)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": {"type": "string"},
+		"nickname": {"type": ["string", "null"]},
+		"aliases": {"type": ["array", "null"], "items": {"type": "string"}},
+		"labels": {"type": ["object", "null"], "properties": {"key": {"type": "string"}}},
+		"manager": {"oneOf": [{"$ref": "#/components/schemas/Blob"}, {"type": "null"}]},
+		"codes": {"type": "array", "items": {"type": "integer", "format": "int32"}}
+	}`, string(b))
+
+	nickname, _ := schema.Properties.Get("nickname")
+	b, err = yaml.Marshal(nickname)
+	assert.NoError(t, err)
+	assert.Equal(t, "type:\n- string\n- \"null\"\n", string(b))
+	var roundTrip types.SchemaObject
+	assert.NoError(t, yaml.Unmarshal(b, &roundTrip))
+	assert.Equal(t, []string{"string", "null"}, roundTrip.Types)
+}
+
+func TestOpenAPI31(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
 	tests := map[string]struct {
-		mode   string
-		format string
+		openAPIVersion string
+		wantInfo       string
+		wantSchema     string
+		wantWebhooks   string
+		wantWarnings   int
 	}{
-		"integration test - yaml": {
-			ModeTest,
-			FormatYAML,
+		"3.0": {
+			openAPIVersion: util.OpenAPIVersion30,
+			wantInfo:       `{"title": "Test Run", "version": "1.0.0", "license": {"name": "MIT"}}`,
+			wantSchema: `{
+				"type": "object",
+				"properties": {
+					"kind": {"type": "string", "enum": ["subscription"]},
+					"seats": {"type": "integer", "format": "int64", "minimum": 0, "exclusiveMinimum": true, "maximum": 100, "example": 5},
+					"status": {"allOf": [{"$ref": "#/components/schemas/Status"}], "example": "active"}
+				}
+			}`,
+			wantWebhooks: `null`,
+			wantWarnings: 3,
 		},
-		"integration test - json": {
-			ModeTest,
-			FormatJSON,
+		"3.1": {
+			openAPIVersion: util.OpenAPIVersion31,
+			wantInfo: `{
+				"title": "Test Run",
+				"summary": "Tests",
+				"version": "1.0.0",
+				"license": {"name": "MIT", "identifier": "MIT"}
+			}`,
+			wantSchema: `{
+				"type": "object",
+				"properties": {
+					"kind": {"type": "string", "const": "subscription"},
+					"seats": {"type": "integer", "format": "int64", "exclusiveMinimum": 0, "maximum": 100, "examples": [5]},
+					"status": {"$ref": "#/components/schemas/Status", "examples": ["active"]}
+				}
+			}`,
+			wantWebhooks: `{
+				"newSubscription": {
+					"post": {"responses": {"200": {"description": "Received"}}, "summary": "New subscription"}
+				}
+			}`,
 		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			modulePath := util.ModulePath("./")
-			path, _ := modulePath.Get()
-			p, _ := newParser(
-				"./",
-				"test/integration/docs.go",
-				"test/integration/pkg/integration_handler",
-				fmt.Sprintf("%s/test/unit", path),
-				false,
-			)
-			test, err := p.CreateOAS("", tc.mode, tc.format)
+			p, err := partialBootstrap()
 			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			p.OpenAPIVersion = tc.openAPIVersion
+
+			assert.NoError(t, p.parseInfo(commentSliceToCommentGroup([]string{
+				"// @Title Test Run",
+				"// @Summary Tests",
+				"// @Version 1.0.0",
+				"// @LicenseName MIT",
+				"// @LicenseIdentifier MIT",
+			})))
+			assert.NoError(t, p.parseOperation(dir, unitPkg, commentSliceToCommentGroup([]string{
+				"// @Title New subscription",
+				`// @Success 200 "Received"`,
+				"// @Webhook newSubscription [post]",
+			})[0].List))
+			_, err = p.parseSchemaObject(dir, unitPkg, "", "unit.Subscription")
+			assert.NoError(t, err)
+			if tc.openAPIVersion == util.OpenAPIVersion31 {
+				types.ConvertToOpenAPI31(&p.OpenAPI)
+			}
+
+			b, _ := json.Marshal(p.OpenAPI.Info)
+			assert.JSONEq(t, tc.wantInfo, string(b))
+			b, _ = json.Marshal(p.OpenAPI.Components.Schemas["Subscription"])
+			assert.JSONEq(t, tc.wantSchema, string(b))
+			b, _ = json.Marshal(p.OpenAPI.Webhooks)
+			assert.JSONEq(t, tc.wantWebhooks, string(b))
+			assert.Len(t, p.Diagnostics, tc.wantWarnings)
+		})
+	}
+}
+
+func TestPathOrder(t *testing.T) {
+	dir, _ := os.Getwd()
+	tests := map[string]struct {
+		pathOrder string
+		wantPaths []string
+	}{
+		"sorted": {
+			pathOrder: util.PathOrderSorted,
+			wantPaths: []string{"/accounts", "/users", "/users/{id}"},
+		},
+		"source": {
+			pathOrder: util.PathOrderSource,
+			wantPaths: []string{"/users/{id}", "/accounts", "/users"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			p.OpenAPI.Paths = types.PathsObject{}
+			p.PathOrder = tc.pathOrder
+			p.DeclarePathParams = true
+			for _, route := range []string{"/users/{id} [get]", "/accounts [get]", "/users [post]", "/users/{id} [put]"} {
+				assert.NoError(t, p.parseOperation(dir, unitPkg, commentSliceToCommentGroup([]string{
+					`// @Success 200 "Ok"`,
+					"// @Route " + route,
+				})[0].List))
+			}
+
+			document, err := p.convertDocument()
+			assert.NoError(t, err)
+			for _, format := range []string{FormatJSON, FormatYAML} {
+				output, err := marshalDocument(document, format)
 				assert.NoError(t, err)
+				var decoded yaml.MapSlice
+				assert.NoError(t, yaml.Unmarshal(output, &decoded))
+				var paths []string
+				for _, item := range decoded {
+					if item.Key == "paths" {
+						for _, path := range item.Value.(yaml.MapSlice) {
+							paths = append(paths, path.Key.(string))
+						}
+					}
+				}
+				assert.Equal(t, tc.wantPaths, paths, format)
 			}
+		})
+	}
+}
+
+func TestValidateDocument(t *testing.T) {
+	p, err := partialBootstrap()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	dir, _ := os.Getwd()
+	src := `package handler
+
+// @Title Get a pet
+// @Param  id  path  string  true  "ID"
+// @Success 204 ""
+// @Router /pets/{id} [get]
+func getPet() {}
+`
+	file, err := goparser.ParseFile(p.Fset, "handler.go", src, goparser.ParseComments)
+	assert.NoError(t, err)
+	p.OpenAPI.Paths = types.PathsObject{}
+	assert.NoError(t, p.parseOperation(dir, unitPkg, file.Comments[0].List))
 
-			assert.NotEmpty(t, test)
+	violations, err := types.ValidateOpenAPI(&p.OpenAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Violation{
+		{Pointer: "/paths/~1pets~1{id}/get/responses/204", Message: "response has no description"},
+	}, violations)
+	assert.Equal(t, "handler.go:5:1", p.sourcePosition(violations[0].Pointer).String())
+	assert.Equal(t, "handler.go:6:1", p.sourcePosition("/paths/~1pets~1{id}/get/summary").String())
+	assert.Equal(t, "handler.go:4:1", p.sourcePosition("/paths/~1pets~1{id}/get/parameters/0/schema").String())
+	assert.Equal(t, p.MainFilePath, p.sourcePosition("/info/title").String())
+
+	assert.NoError(t, p.validateDocument())
+	p.Strict = true
+	assert.Error(t, p.validateDocument())
+}
 
-			var oapi *types.OpenAPIObject
-			switch tc.format {
-			case FormatYAML:
-				err = yaml.Unmarshal([]byte(*test), &oapi)
-			case FormatJSON:
-				err = json.Unmarshal([]byte(*test), &oapi)
+func TestPathParameters(t *testing.T) {
+	dir, _ := os.Getwd()
+	tests := map[string]struct {
+		comments          []string
+		declarePathParams bool
+		wantParameters    []string
+		wantErr           string
+	}{
+		"declared": {
+			comments: []string{
+				`// @Param  owner  path  string  true  "Owner"`,
+				`// @Param  id  path  int  true  "ID"`,
+				`// @Success 200 "Ok"`,
+				"// @Router /owners/{owner}/pets/{id} [get]",
+			},
+			wantParameters: []string{"owner", "id"},
+		},
+		"undeclared": {
+			comments: []string{
+				`// @Param  id  path  int  true  "ID"`,
+				`// @Success 200 "Ok"`,
+				"// @Router /owners/{owner}/pets/{id} [get]",
+			},
+			wantErr: "handler.go:5:1: path parameter owner of route /owners/{owner}/pets/{id} is not declared",
+		},
+		"undeclared are declared as strings": {
+			comments: []string{
+				`// @Param  id  path  int  true  "ID"`,
+				`// @Success 200 "Ok"`,
+				"// @Router /owners/{owner}/pets/{id} [get]",
+			},
+			declarePathParams: true,
+			wantParameters:    []string{"id", "owner"},
+		},
+		"not in the route": {
+			comments: []string{
+				`// @Param  id  path  int  true  "ID"`,
+				`// @Param  userId  path  int  true  "User"`,
+				`// @Success 200 "Ok"`,
+				"// @Router /pets/{id} [get]",
+			},
+			declarePathParams: true,
+			wantErr:           "handler.go:4:1: path parameter userId is not in route /pets/{id}",
+		},
+		"routes with parameters of their own": {
+			comments: []string{
+				`// @Param  owner  path  string  true  "Owner"`,
+				`// @Param  id  path  int  true  "ID"`,
+				`// @Success 200 "Ok"`,
+				"// @Router /owners/{owner}/pets [get]",
+				"// @Router /owners/{owner}/pets/{id} [get]",
+			},
+			wantParameters: []string{"owner", "owner", "id"},
+		},
+		"undeclared in one of the routes": {
+			comments: []string{
+				`// @Param  id  path  int  true  "ID"`,
+				`// @Success 200 "Ok"`,
+				"// @Router /pets/{id} [get]",
+				"// @Router /owners/{owner}/pets/{id} [get]",
+			},
+			wantErr: "handler.go:6:1: path parameter owner of route /owners/{owner}/pets/{id} is not declared",
+		},
+		"not in any of the routes": {
+			comments: []string{
+				`// @Param  id  path  int  true  "ID"`,
+				`// @Param  userId  path  int  true  "User"`,
+				`// @Success 200 "Ok"`,
+				"// @Router /pets [get]",
+				"// @Router /pets/{id} [get]",
+			},
+			wantErr: "handler.go:4:1: path parameter userId is not in route /pets",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			p.OpenAPI.Paths = types.PathsObject{}
+			p.DeclarePathParams = tc.declarePathParams
+			src := "package handler\n\n" + strings.Join(tc.comments, "\n") + "\nfunc handler() {}\n"
+			file, err := goparser.ParseFile(p.Fset, "handler.go", src, goparser.ParseComments)
+			assert.NoError(t, err)
+
+			err = p.parseOperation(dir, unitPkg, file.Comments[0].List)
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			var paths, names []string
+			for path := range p.OpenAPI.Paths {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			for _, path := range paths {
+				for _, parameter := range p.OpenAPI.Paths[path].Get.Parameters {
+					assert.Equal(t, types.InPath, parameter.In)
+					assert.True(t, parameter.Required)
+					names = append(names, parameter.Name)
+				}
+			}
+			assert.Equal(t, tc.wantParameters, names)
+		})
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	src := `package handler
+
+// @Title Get a pet
+// @Param  id  path  string
+// @Success ok {object} unit.Citrus "Pet"
+// @Webhook newPet [post]
+// @Router /pets/{id} [get]
+func getPet() {}
+`
+	tests := map[string]struct {
+		allErrors       bool
+		wantErr         string
+		wantDiagnostics []string
+	}{
+		"first error": {
+			wantErr: `handler.go:4:1: parseParamComment: can not parse @param comment "id  path  string"`,
+		},
+		"all errors": {
+			allErrors: true,
+			wantDiagnostics: []string{
+				`handler.go:4:1: error: parseParamComment: can not parse @param comment "id  path  string" (parser.can-not-parse-comment)`,
+				"handler.go:5:1: error: parseResponseComment: http status must be int, but got ok (parser.unexpected-type)",
+				"handler.go:6:1: warning: @Webhook is only supported by OpenAPI 3.1",
+				"handler.go:7:1: error: path parameter id of route /pets/{id} is not declared",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			p.OpenAPI.Paths = types.PathsObject{}
+			p.AllErrors = tc.allErrors
+			file, err := goparser.ParseFile(p.Fset, "handler.go", src, goparser.ParseComments)
+			assert.NoError(t, err)
+
+			err = p.parseOperation(dir, unitPkg, file.Comments[0].List)
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			if assert.Len(t, p.Diagnostics, len(tc.wantDiagnostics)) {
+				for i, diagnostic := range p.Diagnostics {
+					assert.Contains(t, diagnostic.String(), tc.wantDiagnostics[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWrappedDiagnostics(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	tests := map[string]struct {
+		comment string
+		wantErr string
+	}{
+		"param": {
+			comment: `// @Param  ids  query  []Missing  false  "IDs"`,
+			wantErr: "handler.go:3:1: error: parseParamComment: unable to handle params: handleParam: can not parse goType []Missing: " +
+				"can not find definition of Missing ast.TypeSpec in package " + unitPkg + " (parser.missing-definition)",
+		},
+		"body": {
+			comment: `// @Param  ids  body  []Missing  true  "IDs"`,
+			wantErr: "handler.go:3:1: error: parseParamComment: can not parse goType []Missing: " +
+				"can not find definition of Missing ast.TypeSpec in package " + unitPkg + " (parser.missing-definition)",
+		},
+		"response": {
+			comment: `// @Success 200 {object} []Missing "IDs"`,
+			wantErr: "handler.go:3:1: error: parseResponseComment: can not parse goType []Missing: " +
+				"can not find definition of Missing ast.TypeSpec in package " + unitPkg + " (parser.missing-definition)",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
 			}
+			p.OpenAPI.Paths = types.PathsObject{}
+			src := "package handler\n\n" + tc.comment + "\n// @Router /ids [get]\nfunc handler() {}\n"
+			file, err := goparser.ParseFile(p.Fset, "handler.go", src, goparser.ParseComments)
 			assert.NoError(t, err)
 
-			assert.Equal(t, "3.0.0", oapi.OpenAPI)
-			assert.Equal(t, "Swagger Pet Store", oapi.Info.Title)
-			assert.Equal(t, "MIT", oapi.Info.License.Name)
-			assert.Equal(t, "http://petstore.swagger.io/v1", oapi.Servers[0].URL)
-			assert.Equal(t, "List all pets", oapi.Paths["/pets"].Get.Summary)
-			assert.Equal(t, "listPets", oapi.Paths["/pets"].Get.OperationID)
-			assert.Equal(t, "object", oapi.Components.Schemas["Pet"].Type)
-			id, ok := oapi.Components.Schemas["Pet"].Properties.Get("id")
-			strictID := id.(orderedmap.OrderedMap)
-			propertyType, _ := strictID.Get("type")
-			assert.True(t, ok)
-			assert.Equal(t, "integer", propertyType)
+			err = p.parseOperation(dir, unitPkg, file.Comments[0].List)
+			var diagnostic types.Diagnostic
+			if assert.ErrorAs(t, err, &diagnostic) {
+				assert.Equal(t, tc.wantErr, diagnostic.String())
+			}
 		})
 	}
 }
@@ -2510,6 +3595,16 @@ func commentSliceToCommentGroup(commentSlice []string) []*ast.CommentGroup {
 	return fileComments
 }
 
+// unitPkg is the package the annotations of the tests are resolved in, as if they were written in it
+const unitPkg = "github.com/deanstalker/goas/test/unit"
+
+var (
+	loadModuleOnce sync.Once
+	loadedModule   *parser
+	loadModuleErr  error
+)
+
+// partialBootstrap type-checks the module once, and shares the loaded packages between parsers
 func partialBootstrap() (*parser, error) {
 	modulePath := util.ModulePath("./")
 	path, _ := modulePath.Get()
@@ -2517,19 +3612,129 @@ func partialBootstrap() (*parser, error) {
 		"./",
 		"./main.go",
 		"",
-		fmt.Sprintf("%s/test/integration,%s/test/integration/pkg/integration_handler", path, path),
+		fmt.Sprintf("%s/test/integration,%s/test/integration/pkg/integration_handler,%s/test/resolve", path, path, path),
 		false,
 	)
 	if err != nil {
 		return nil, err
 	}
-	p.parseModule()
-	if err := p.parseGoMod(); err != nil {
-		return nil, err
+	loadModuleOnce.Do(func() {
+		loadedModule = p
+		loadModuleErr = p.parseModule(context.Background())
+	})
+	if loadModuleErr != nil {
+		return nil, loadModuleErr
 	}
+	p.Fset = loadedModule.Fset
+	p.Packages = loadedModule.Packages
+	p.KnownPkgs = loadedModule.KnownPkgs
 	if err := p.parseAPIs(); err != nil {
 		return nil, err
 	}
 
 	return p, nil
 }
+
+func TestUnresolvedType(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	src := `package handler
+
+// @Title Get a pet
+// @Success 200 {object} %s "Pet"
+// @Router /pets [get]
+func getPet() {}
+`
+	tests := map[string]struct {
+		goType          string
+		bestEffort      bool
+		wantErr         string
+		wantSchema      string
+		wantUnresolved  bool
+		wantDiagnostics []string
+	}{
+		"fails": {
+			goType:  "unit.Unknown",
+			wantErr: "handler.go:4:1: ",
+		},
+		"best effort": {
+			goType:         "unit.Unknown",
+			bestEffort:     true,
+			wantSchema:     `{"$ref": "#/components/schemas/unit.Unknown"}`,
+			wantUnresolved: true,
+			wantDiagnostics: []string{
+				"handler.go:4:1: warning: can not find definition of unit.Unknown in package github.com/deanstalker/goas/test/unit, it is documented as an empty schema (parser.unresolved-type)",
+			},
+		},
+		"best effort predeclared type": {
+			goType:     "any",
+			bestEffort: true,
+			wantSchema: `{}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := partialBootstrap()
+			if err != nil {
+				t.Fatalf("%v", err)
+			}
+			p.OpenAPI.Paths = types.PathsObject{}
+			p.BestEffort = tc.bestEffort
+			file, err := goparser.ParseFile(p.Fset, "handler.go", fmt.Sprintf(src, tc.goType), goparser.ParseComments)
+			assert.NoError(t, err)
+
+			err = p.parseOperation(dir, unitPkg, file.Comments[0].List)
+			if tc.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.wantErr)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			b, _ := json.Marshal(p.OpenAPI.Paths["/pets"].Get.Responses["200"].Content[types.ContentTypeJSON].Schema)
+			assert.JSONEq(t, tc.wantSchema, string(b))
+			if tc.wantUnresolved {
+				b, _ = json.Marshal(p.OpenAPI.Components.Schemas["unit.Unknown"])
+				assert.JSONEq(t, `{"x-goas-unresolved": "unit.Unknown"}`, string(b))
+			}
+			var diagnostics []string
+			for _, diagnostic := range p.Diagnostics {
+				diagnostics = append(diagnostics, diagnostic.String())
+			}
+			assert.Equal(t, tc.wantDiagnostics, diagnostics)
+		})
+	}
+}
+
+func TestUnresolvedParamType(t *testing.T) {
+	gotext.Configure("./locales", "en", "default")
+	dir, _ := os.Getwd()
+	src := `package handler
+
+// @Title List pets
+// @Param  status  query  unit.Unknown  false  "Status"
+// @Success 200 "Pets"
+// @Router /pets [get]
+func listPets() {}
+`
+	p, err := partialBootstrap()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	p.OpenAPI.Paths = types.PathsObject{}
+	p.BestEffort = true
+	file, err := goparser.ParseFile(p.Fset, "handler.go", src, goparser.ParseComments)
+	assert.NoError(t, err)
+
+	assert.NoError(t, p.parseOperation(dir, unitPkg, file.Comments[0].List))
+	b, _ := json.Marshal(p.OpenAPI.Paths["/pets"].Get.Parameters)
+	assert.JSONEq(t, `[{"name": "status", "in": "query", "description": "Status", "schema": {"$ref": "#/components/schemas/unit.Unknown"}}]`, string(b))
+	var diagnostics []string
+	for _, diagnostic := range p.Diagnostics {
+		diagnostics = append(diagnostics, diagnostic.String())
+	}
+	assert.Equal(t, []string{
+		"handler.go:4:1: warning: can not find definition of unit.Unknown in package github.com/deanstalker/goas/test/unit, it is documented as an empty schema (parser.unresolved-type)",
+	}, diagnostics)
+}
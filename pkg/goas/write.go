@@ -0,0 +1,157 @@
+package goas
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+	"gopkg.in/yaml.v2"
+
+	"github.com/deanstalker/goas/pkg/types"
+)
+
+// Modes documents are written in
+const (
+	ModeStdOut     = "stdout"
+	ModeFileWriter = "file"
+	ModeDirWriter  = "dir"
+	ModeCheck      = "check"
+	ModeDirCheck   = "dircheck"
+	ModeTest       = "test"
+)
+
+// Formats documents are written in
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// WriteDocument writes a document in format to the file at path or stdout, or returns it in test mode. In dir mode
+// the document is split into the directory at path, see types.SplitDocument, and in the check modes it is compared to
+// the file or directory at path instead of written.
+func WriteDocument(document interface{}, path, mode, format string) (*string, error) {
+	switch mode {
+	case ModeDirWriter, ModeDirCheck:
+		files, err := types.SplitDocument(document, format)
+		if err != nil {
+			return nil, err
+		}
+		paths := make(map[string]interface{}, len(files))
+		for name, file := range files {
+			paths[filepath.Join(path, filepath.FromSlash(name))] = file
+		}
+		if mode == ModeDirCheck {
+			return nil, checkFiles(paths, format)
+		}
+		return nil, writeFiles(paths, format)
+	case ModeCheck:
+		return nil, checkFiles(map[string]interface{}{path: document}, format)
+	}
+	return writeFile(document, path, mode, format)
+}
+
+// writeFile marshals a document in format, and writes it to the file at path, stdout, or returns it in test mode
+func writeFile(document interface{}, path, mode, format string) (*string, error) {
+	output, err := marshalDocument(document, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var fd *os.File
+	switch mode {
+	case ModeFileWriter:
+		fd, err = os.Create(path)
+		if err != nil {
+			return nil, errorf("error.io.write-error", path, err)
+		}
+		defer fd.Close()
+		_, _ = fd.WriteString(string(output))
+	case ModeStdOut:
+		_, err = os.Stdout.WriteString(string(output))
+	case ModeTest:
+		test := string(output)
+		return &test, nil
+	}
+
+	return nil, err
+}
+
+func marshalDocument(document interface{}, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(document, "", "  ")
+	case FormatYAML:
+		return yaml.Marshal(document)
+	}
+	return nil, nil
+}
+
+// writeFiles writes documents to the files they are keyed by, creating their directories
+func writeFiles(files map[string]interface{}, format string) error {
+	for _, path := range sortedPaths(files) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return errorf("error.io.write-error", path, err)
+		}
+		if _, err := writeFile(files[path], path, ModeFileWriter, format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFiles compares documents to the files they are keyed by, regardless of how the files are formatted. The
+// differences are printed, and an error is returned if any file is missing or out of date.
+func checkFiles(files map[string]interface{}, format string) error {
+	var stale []string
+	for _, path := range sortedPaths(files) {
+		output, err := marshalDocument(files[path], format)
+		if err != nil {
+			return err
+		}
+		generated, err := types.DecodeDocument(output)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			stale = append(stale, path)
+			fmt.Println(gotext.Get("check.missing", path))
+			continue
+		}
+		if err != nil {
+			return errorf("error.io.read-error", path, err)
+		}
+		current, err := types.DecodeDocument(data)
+		if err != nil {
+			return errorf("error.io.read-error", path, err)
+		}
+
+		differences := types.DiffDocuments(current, generated)
+		if len(differences) == 0 {
+			continue
+		}
+		stale = append(stale, path)
+		fmt.Println(gotext.Get("check.out-of-date", path))
+		for _, difference := range differences {
+			fmt.Println("  " + difference.String())
+		}
+	}
+	if len(stale) > 0 {
+		return errorf("error.check.stale", strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+func sortedPaths(files map[string]interface{}) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,544 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ChangePathRemoved               = "path-removed"
+	ChangePathAdded                 = "path-added"
+	ChangeOperationRemoved          = "operation-removed"
+	ChangeOperationAdded            = "operation-added"
+	ChangeParameterAdded            = "parameter-added"
+	ChangeParameterRemoved          = "parameter-removed"
+	ChangeParameterBecameRequired   = "parameter-became-required"
+	ChangeParameterBecameOptional   = "parameter-became-optional"
+	ChangeRequestBodyBecameRequired = "request-body-became-required"
+	ChangeContentTypeRemoved        = "content-type-removed"
+	ChangeContentTypeAdded          = "content-type-added"
+	ChangeResponseRemoved           = "response-removed"
+	ChangeResponseAdded             = "response-added"
+	ChangeTypeChanged               = "type-changed"
+	ChangeFormatChanged             = "format-changed"
+	ChangePropertyAdded             = "property-added"
+	ChangePropertyRemoved           = "property-removed"
+	ChangePropertyBecameRequired    = "property-became-required"
+	ChangePropertyBecameOptional    = "property-became-optional"
+	ChangeEnumValuesRemoved         = "enum-values-removed"
+	ChangeEnumValuesAdded           = "enum-values-added"
+	ChangeConstraintTightened       = "constraint-tightened"
+	ChangeConstraintLoosened        = "constraint-loosened"
+)
+
+// Change between two OpenAPI documents, classified by Rule, at Location in the document
+type Change struct {
+	Breaking bool   `json:"breaking"`
+	Rule     string `json:"rule"`
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+// String renders the change as a line of a report
+func (c Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "BREAKING"
+	}
+	return fmt.Sprintf("%-12s %s: %s", kind, c.Location, c.Message)
+}
+
+// ReadDocument reads a document from a json or yaml file, decoded as is
+func ReadDocument(file string) (interface{}, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := DecodeDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return decoded, nil
+}
+
+// ReadOpenAPIDocument reads an OpenAPI 3 document from a json or yaml file
+func ReadOpenAPIDocument(file string) (*OpenAPIObject, error) {
+	decoded, err := ReadDocument(file)
+	if err != nil {
+		return nil, err
+	}
+	if document, ok := decoded.(map[string]interface{}); !ok || document["openapi"] == nil {
+		return nil, fmt.Errorf("%s: not an OpenAPI 3 document", file)
+	}
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+	var document OpenAPIObject
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return &document, nil
+}
+
+// DiffOpenAPI lists the changes from old to new that affect clients of the API, classified as breaking or not.
+// Parameters, request bodies, responses and schemas are compared where operations use them, following their $refs to
+// the components of the document, and operations inherit the parameters of their path item. Changes to a request break clients that
+// were valid before if they narrow what is accepted, and changes to a response if they widen what may be returned.
+func DiffOpenAPI(old, new *OpenAPIObject) []Change {
+	d := &differ{old: old, new: new, comparing: map[[2]*SchemaObject]bool{}}
+	for _, path := range sortedKeys(old.Paths) {
+		location := "paths." + path
+		newPathItem, ok := new.Paths[path]
+		if !ok {
+			d.add(true, ChangePathRemoved, location, "path removed")
+			continue
+		}
+		d.diffPathItem(location, old.Paths[path], newPathItem)
+	}
+	for _, path := range sortedKeys(new.Paths) {
+		if _, ok := old.Paths[path]; !ok {
+			d.add(false, ChangePathAdded, "paths."+path, "path added")
+		}
+	}
+	return d.changes
+}
+
+type differ struct {
+	old, new *OpenAPIObject
+	changes  []Change
+
+	// comparing are the pairs of schemas being compared, so that recursive schemas are not compared endlessly
+	comparing map[[2]*SchemaObject]bool
+}
+
+func (d *differ) add(breaking bool, rule, location, message string, args ...interface{}) {
+	d.changes = append(d.changes, Change{
+		Breaking: breaking,
+		Rule:     rule,
+		Location: location,
+		Message:  fmt.Sprintf(message, args...),
+	})
+}
+
+var operationMethods = []string{"get", "post", "patch", "put", "delete", "options", "head", "trace"}
+
+func (d *differ) diffPathItem(location string, old, new *PathItemObject) {
+	oldOperations := pathItemOperations(old)
+	newOperations := pathItemOperations(new)
+	for _, method := range operationMethods {
+		oldOperation, newOperation := oldOperations[method], newOperations[method]
+		switch {
+		case oldOperation != nil && newOperation == nil:
+			d.add(true, ChangeOperationRemoved, location+"."+method, "operation removed")
+		case oldOperation == nil && newOperation != nil:
+			d.add(false, ChangeOperationAdded, location+"."+method, "operation added")
+		case oldOperation != nil:
+			d.diffParameters(location+"."+method+".parameters", operationParameters(d.old, old, oldOperation), operationParameters(d.new, new, newOperation))
+			d.diffOperation(location+"."+method, oldOperation, newOperation)
+		}
+	}
+}
+
+func pathItemOperations(p *PathItemObject) map[string]*OperationObject {
+	return map[string]*OperationObject{
+		"get": p.Get, "post": p.Post, "patch": p.Patch, "put": p.Put,
+		"delete": p.Delete, "options": p.Options, "head": p.Head, "trace": p.Trace,
+	}
+}
+
+// operationParameters are the parameters of an operation and those of its path item the operation does not override,
+// their $refs resolved
+func operationParameters(document *OpenAPIObject, pathItem *PathItemObject, operation *OperationObject) []ParameterObject {
+	var parameters []ParameterObject
+	declared := map[string]bool{}
+	for _, list := range [][]ParameterObject{operation.Parameters, pathItem.Parameters} {
+		for i := range list {
+			parameter := *resolveComponent(document.Components.Parameters, "parameters", &list[i], func(p *ParameterObject) string { return p.Ref })
+			if !declared[parameter.In+" "+parameter.Name] {
+				declared[parameter.In+" "+parameter.Name] = true
+				parameters = append(parameters, parameter)
+			}
+		}
+	}
+	return parameters
+}
+
+// resolveComponent follows the $refs of an object to the components of kind, the object is returned as is if they
+// do not resolve
+func resolveComponent[T any](components map[string]*T, kind string, object *T, ref func(*T) string) *T {
+	prefix := "#/components/" + kind + "/"
+	for i := 0; object != nil && ref(object) != "" && i <= len(components); i++ {
+		if !strings.HasPrefix(ref(object), prefix) {
+			return object
+		}
+		resolved, ok := components[strings.TrimPrefix(ref(object), prefix)]
+		if !ok || resolved == nil {
+			return object
+		}
+		object = resolved
+	}
+	return object
+}
+
+func (d *differ) diffOperation(location string, old, new *OperationObject) {
+	if old.RequestBody != nil || new.RequestBody != nil {
+		ref := func(body *RequestBodyObject) string { return body.Ref }
+		oldBody := resolveComponent(d.old.Components.RequestBodies, "requestBodies", old.RequestBody, ref)
+		newBody := resolveComponent(d.new.Components.RequestBodies, "requestBodies", new.RequestBody, ref)
+		if oldBody == nil {
+			oldBody = &RequestBodyObject{}
+		}
+		if newBody == nil {
+			newBody = &RequestBodyObject{}
+		}
+		bodyLocation := location + ".requestBody"
+		if newBody.Required && !oldBody.Required {
+			d.add(true, ChangeRequestBodyBecameRequired, bodyLocation, "request body became required")
+		}
+		d.diffContent(bodyLocation+".content", oldBody.Content, newBody.Content, true)
+	}
+
+	ref := func(response *ResponseObject) string { return response.Ref }
+	for _, status := range sortedKeys(old.Responses) {
+		responseLocation := location + ".responses." + status
+		newResponse, ok := new.Responses[status]
+		if !ok {
+			d.add(true, ChangeResponseRemoved, responseLocation, "response removed")
+			continue
+		}
+		oldResponse := resolveComponent(d.old.Components.Responses, "responses", old.Responses[status], ref)
+		newResponse = resolveComponent(d.new.Components.Responses, "responses", newResponse, ref)
+		if oldResponse != nil && newResponse != nil {
+			d.diffContent(responseLocation+".content", oldResponse.Content, newResponse.Content, false)
+		}
+	}
+	for _, status := range sortedKeys(new.Responses) {
+		if _, ok := old.Responses[status]; !ok {
+			d.add(false, ChangeResponseAdded, location+".responses."+status, "response added")
+		}
+	}
+}
+
+func (d *differ) diffParameters(location string, old, new []ParameterObject) {
+	key := func(parameter ParameterObject) string {
+		return parameter.In + " " + parameter.Name
+	}
+	newParameters := map[string]ParameterObject{}
+	for _, parameter := range new {
+		newParameters[key(parameter)] = parameter
+	}
+	oldParameters := map[string]ParameterObject{}
+	for _, oldParameter := range old {
+		oldParameters[key(oldParameter)] = oldParameter
+		parameterLocation := location + "." + oldParameter.In + "." + oldParameter.Name
+		newParameter, ok := newParameters[key(oldParameter)]
+		if !ok {
+			// clients sending the parameter, even an optional one, are rejected or no longer get what they asked for
+			d.add(true, ChangeParameterRemoved, parameterLocation, "parameter removed")
+			continue
+		}
+		if newParameter.Required && !oldParameter.Required {
+			d.add(true, ChangeParameterBecameRequired, parameterLocation, "parameter became required")
+		}
+		if !newParameter.Required && oldParameter.Required {
+			d.add(false, ChangeParameterBecameOptional, parameterLocation, "parameter became optional")
+		}
+		if oldParameter.Schema != nil && newParameter.Schema != nil {
+			d.diffSchema(parameterLocation+".schema", oldParameter.Schema, newParameter.Schema, true)
+		}
+		if oldParameter.Content != nil || newParameter.Content != nil {
+			d.diffContent(parameterLocation+".content", oldParameter.Content, newParameter.Content, true)
+		}
+	}
+	for _, newParameter := range new {
+		if _, ok := oldParameters[key(newParameter)]; !ok {
+			parameterLocation := location + "." + newParameter.In + "." + newParameter.Name
+			if newParameter.Required {
+				d.add(true, ChangeParameterAdded, parameterLocation, "required parameter added")
+			} else {
+				d.add(false, ChangeParameterAdded, parameterLocation, "optional parameter added")
+			}
+		}
+	}
+}
+
+// diffContent compares the content of a request, or of a response. A request no longer accepting a content type
+// breaks clients sending it, a response no longer returning a content type does not.
+func (d *differ) diffContent(location string, old, new map[string]*MediaTypeObject, request bool) {
+	for _, contentType := range sortedKeys(old) {
+		contentLocation := location + "." + contentType
+		newMediaType, ok := new[contentType]
+		if !ok {
+			d.add(request, ChangeContentTypeRemoved, contentLocation, "content type removed")
+			continue
+		}
+		d.diffSchema(contentLocation+".schema", &old[contentType].Schema, &newMediaType.Schema, request)
+	}
+	for _, contentType := range sortedKeys(new) {
+		if _, ok := old[contentType]; !ok {
+			d.add(!request, ChangeContentTypeAdded, location+"."+contentType, "content type added")
+		}
+	}
+}
+
+// diffSchema compares the schemas of a request, or of a response
+func (d *differ) diffSchema(location string, old, new *SchemaObject, request bool) {
+	old, new = resolveSchema(d.old, old), resolveSchema(d.new, new)
+	pair := [2]*SchemaObject{old, new}
+	if old == nil || new == nil || d.comparing[pair] {
+		return
+	}
+	d.comparing[pair] = true
+	defer delete(d.comparing, pair)
+
+	oldTypes, newTypes := schemaTypes(old), schemaTypes(new)
+	if len(oldTypes) > 0 && len(newTypes) > 0 && strings.Join(oldTypes, ",") != strings.Join(newTypes, ",") {
+		d.add(true, ChangeTypeChanged, location+".type", "type changed from %s to %s", strings.Join(oldTypes, ", "), strings.Join(newTypes, ", "))
+		return
+	}
+	if old.Format != new.Format && old.Format != "" && new.Format != "" {
+		d.add(true, ChangeFormatChanged, location+".format", "format changed from %s to %s", old.Format, new.Format)
+	}
+
+	d.diffEnum(location+".enum", old.Enum, new.Enum, request)
+	d.diffConstraints(location, old, new, request)
+	d.diffProperties(location, old, new, request)
+
+	if old.Items != nil && new.Items != nil {
+		d.diffSchema(location+".items", old.Items, new.Items, request)
+	}
+	if old.AdditionalProperties != nil && new.AdditionalProperties != nil {
+		d.diffSchema(location+".additionalProperties", old.AdditionalProperties, new.AdditionalProperties, request)
+	}
+	for i := 0; i < len(old.AllOf) && i < len(new.AllOf); i++ {
+		d.diffSchema(fmt.Sprintf("%s.allOf[%d]", location, i), old.AllOf[i], new.AllOf[i], request)
+	}
+}
+
+// resolveSchema follows the $refs of a schema to the components of the document
+func resolveSchema(document *OpenAPIObject, schema *SchemaObject) *SchemaObject {
+	for i := 0; schema != nil && schema.Ref != "" && i < len(document.Components.Schemas)+1; i++ {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		resolved, ok := document.Components.Schemas[name]
+		if !ok || name == schema.Ref {
+			return schema
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+// schemaTypes are the types of a schema, without null
+func schemaTypes(schema *SchemaObject) []string {
+	var types []string
+	for _, t := range append([]string{schema.Type}, schema.Types...) {
+		if t != "" && t != TypeNull {
+			types = append(types, t)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (d *differ) diffEnum(location string, old, new []interface{}, request bool) {
+	if len(old) == 0 && len(new) == 0 {
+		return
+	}
+	removed, added := enumDifference(old, new), enumDifference(new, old)
+	// a schema without an enum accepts any value
+	if len(old) == 0 {
+		removed, added = []string{"any"}, nil
+	}
+	if len(new) == 0 {
+		removed, added = nil, []string{"any"}
+	}
+	if len(removed) > 0 {
+		d.add(request, ChangeEnumValuesRemoved, location, "enum values %s removed", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		d.add(!request, ChangeEnumValuesAdded, location, "enum values %s added", strings.Join(added, ", "))
+	}
+}
+
+// enumDifference lists the values of a that are not in b
+func enumDifference(a, b []interface{}) []string {
+	in := map[string]bool{}
+	for _, value := range b {
+		in[fmt.Sprint(value)] = true
+	}
+	var difference []string
+	for _, value := range a {
+		if !in[fmt.Sprint(value)] {
+			difference = append(difference, fmt.Sprint(value))
+		}
+	}
+	return difference
+}
+
+func (d *differ) diffConstraints(location string, old, new *SchemaObject, request bool) {
+	bound := func(name string, oldValue, newValue interface{}, upper bool) {
+		oldBound, oldSet := constraintValue(oldValue)
+		newBound, newSet := constraintValue(newValue)
+		if !oldSet && !newSet || oldSet && newSet && oldBound == newBound {
+			return
+		}
+		// a bound that is added tightens the schema, one that is removed loosens it
+		tightened := !oldSet || newSet && (upper && newBound < oldBound || !upper && newBound > oldBound)
+		d.constraintChange(location+"."+name, tightened, request, fmt.Sprintf("%s changed from %s to %s", name, constraintString(oldValue, oldSet), constraintString(newValue, newSet)))
+	}
+	numericBound := func(name string, oldValue, oldExclusive, newValue, newExclusive interface{}, upper bool) {
+		oldBound, oldSet, oldStrict := exclusiveBound(oldValue, oldExclusive, upper)
+		newBound, newSet, newStrict := exclusiveBound(newValue, newExclusive, upper)
+		if !oldSet && !newSet || oldSet && newSet && oldBound == newBound && oldStrict == newStrict {
+			return
+		}
+		tightened := !oldSet || newSet && (upper && newBound < oldBound || !upper && newBound > oldBound || newBound == oldBound && newStrict)
+		d.constraintChange(location+"."+name, tightened, request, fmt.Sprintf("%s changed from %s to %s", name, boundString(oldBound, oldSet, oldStrict), boundString(newBound, newSet, newStrict)))
+	}
+	numericBound("maximum", old.Maximum, old.ExclusiveMaximum, new.Maximum, new.ExclusiveMaximum, true)
+	numericBound("minimum", old.Minimum, old.ExclusiveMinimum, new.Minimum, new.ExclusiveMinimum, false)
+	bound("maxLength", old.MaxLength, new.MaxLength, true)
+	bound("minLength", old.MinLength, new.MinLength, false)
+	bound("maxItems", intConstraint(old.MaxItems), intConstraint(new.MaxItems), true)
+	bound("minItems", intConstraint(old.MinItems), intConstraint(new.MinItems), false)
+	bound("maxProperties", intConstraint(old.MaxProperties), intConstraint(new.MaxProperties), true)
+	bound("minProperties", intConstraint(old.MinProperties), intConstraint(new.MinProperties), false)
+
+	if old.Pattern != new.Pattern {
+		d.constraintChange(location+".pattern", new.Pattern != "", request, fmt.Sprintf("pattern changed from %q to %q", old.Pattern, new.Pattern))
+	}
+}
+
+// exclusiveBound returns the bound of minimum or maximum, and whether it is exclusive, given exclusiveMinimum or
+// exclusiveMaximum: a bool that makes the bound exclusive in 3.0, or a bound of its own in 3.1, the tighter one of
+// the two applying
+func exclusiveBound(bound, exclusive interface{}, upper bool) (float64, bool, bool) {
+	value, set := constraintValue(bound)
+	if isExclusive, ok := exclusive.(bool); ok {
+		return value, set, set && isExclusive
+	}
+	if exclusiveValue, ok := constraintValue(exclusive); ok {
+		if !set || upper && exclusiveValue <= value || !upper && exclusiveValue >= value {
+			return exclusiveValue, true, true
+		}
+	}
+	return value, set, false
+}
+
+func boundString(value float64, set, exclusive bool) string {
+	if !set {
+		return "none"
+	}
+	if exclusive {
+		return strconv.FormatFloat(value, 'f', -1, 64) + " exclusive"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func (d *differ) constraintChange(location string, tightened, request bool, message string) {
+	if tightened {
+		d.add(request, ChangeConstraintTightened, location, "%s", message)
+	} else {
+		d.add(!request, ChangeConstraintLoosened, location, "%s", message)
+	}
+}
+
+// intConstraint is the value of a constraint of an int field, which is not set when 0
+func intConstraint(value int) interface{} {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+// constraintValue of a numeric constraint, and whether it is set
+func constraintValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func constraintString(value interface{}, set bool) string {
+	if !set {
+		return "none"
+	}
+	return fmt.Sprint(value)
+}
+
+func (d *differ) diffProperties(location string, old, new *SchemaObject, request bool) {
+	oldRequired, newRequired := stringSet(old.Required), stringSet(new.Required)
+	oldProperties, newProperties := schemaProperties(old), schemaProperties(new)
+
+	for _, name := range sortedKeys(oldProperties) {
+		propertyLocation := location + ".properties." + name
+		newProperty, ok := newProperties[name]
+		if !ok {
+			// clients reading a response may rely on the property, servers ignore properties they do not know
+			d.add(!request, ChangePropertyRemoved, propertyLocation, "property removed")
+			continue
+		}
+		if newRequired[name] && !oldRequired[name] {
+			d.add(request, ChangePropertyBecameRequired, propertyLocation, "property became required")
+		}
+		if oldRequired[name] && !newRequired[name] {
+			d.add(!request, ChangePropertyBecameOptional, propertyLocation, "property became optional")
+		}
+		d.diffSchema(propertyLocation, oldProperties[name], newProperty, request)
+	}
+	for _, name := range sortedKeys(newProperties) {
+		if _, ok := oldProperties[name]; ok {
+			continue
+		}
+		if newRequired[name] {
+			d.add(request, ChangePropertyAdded, location+".properties."+name, "required property added")
+		} else {
+			d.add(false, ChangePropertyAdded, location+".properties."+name, "optional property added")
+		}
+	}
+}
+
+// schemaProperties of a schema, properties that were read from a document are decoded as schemas
+func schemaProperties(schema *SchemaObject) map[string]*SchemaObject {
+	properties := map[string]*SchemaObject{}
+	if schema.Properties == nil {
+		return properties
+	}
+	for _, key := range schema.Properties.Keys() {
+		value, _ := schema.Properties.Get(key)
+		if property, ok := value.(*SchemaObject); ok {
+			properties[key] = property
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		var property SchemaObject
+		if err := json.Unmarshal(data, &property); err == nil {
+			properties[key] = &property
+		}
+	}
+	return properties
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,223 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffOpenAPI(t *testing.T) {
+	document := func(pet *SchemaObject, parameters []ParameterObject, responses ResponsesObject) *OpenAPIObject {
+		return &OpenAPIObject{
+			Paths: PathsObject{
+				"/pets": {
+					Get: &OperationObject{Parameters: parameters, Responses: responses},
+					Post: &OperationObject{
+						RequestBody: &RequestBodyObject{
+							Content: map[string]*MediaTypeObject{ContentTypeJSON: {Schema: SchemaObject{Ref: "#/components/schemas/Pet"}}},
+						},
+						Responses: ResponsesObject{"201": {Description: "Created"}},
+					},
+				},
+			},
+			Components: ComponentsObject{Schemas: map[string]*SchemaObject{"Pet": pet}},
+		}
+	}
+	pet := func(mutate func(pet *SchemaObject)) *SchemaObject {
+		schema := &SchemaObject{
+			Type:     TypeObject,
+			Required: []string{"name"},
+			Properties: NewOrderedMap().
+				Set("name", &SchemaObject{Type: TypeString, MaxLength: 50}).
+				Set("kind", &SchemaObject{Type: TypeString, Enum: []interface{}{"cat", "dog"}}).
+				Set("age", &SchemaObject{Type: TypeInteger, Minimum: 0}),
+		}
+		if mutate != nil {
+			mutate(schema)
+		}
+		return schema
+	}
+	responses := func(statuses ...string) ResponsesObject {
+		r := ResponsesObject{}
+		for _, status := range statuses {
+			r[status] = &ResponseObject{
+				Description: status,
+				Content: map[string]*MediaTypeObject{ContentTypeJSON: {Schema: SchemaObject{
+					Type:  TypeArray,
+					Items: &SchemaObject{Ref: "#/components/schemas/Pet"},
+				}}},
+			}
+		}
+		return r
+	}
+	limit := ParameterObject{Name: "limit", In: "query", Schema: &SchemaObject{Type: TypeInteger, Maximum: 100}}
+
+	tests := map[string]struct {
+		old, new    *OpenAPIObject
+		wantChanges []Change
+	}{
+		"unchanged": {
+			old: document(pet(nil), []ParameterObject{limit}, responses("200")),
+			new: document(pet(nil), []ParameterObject{limit}, responses("200")),
+		},
+		"paths and operations": {
+			old: document(pet(nil), nil, responses("200")),
+			new: &OpenAPIObject{Paths: PathsObject{
+				"/pets":   {Get: &OperationObject{Responses: responses("200")}},
+				"/owners": {Get: &OperationObject{Responses: responses("200")}},
+			}, Components: ComponentsObject{Schemas: map[string]*SchemaObject{"Pet": pet(nil)}}},
+			wantChanges: []Change{
+				{Breaking: true, Rule: ChangeOperationRemoved, Location: "paths./pets.post", Message: "operation removed"},
+				{Breaking: false, Rule: ChangePathAdded, Location: "paths./owners", Message: "path added"},
+			},
+		},
+		"parameters and responses": {
+			old: document(pet(nil), []ParameterObject{limit}, responses("200", "404")),
+			new: document(pet(nil), []ParameterObject{
+				{Name: "limit", In: "query", Schema: &SchemaObject{Type: TypeInteger, Maximum: 50}},
+				{Name: "owner", In: "query", Required: true, Schema: &SchemaObject{Type: TypeString}},
+				{Name: "sort", In: "query", Schema: &SchemaObject{Type: TypeString}},
+			}, responses("200", "500")),
+			wantChanges: []Change{
+				{Breaking: true, Rule: ChangeConstraintTightened, Location: "paths./pets.get.parameters.query.limit.schema.maximum", Message: "maximum changed from 100 to 50"},
+				{Breaking: true, Rule: ChangeParameterAdded, Location: "paths./pets.get.parameters.query.owner", Message: "required parameter added"},
+				{Breaking: false, Rule: ChangeParameterAdded, Location: "paths./pets.get.parameters.query.sort", Message: "optional parameter added"},
+				{Breaking: true, Rule: ChangeResponseRemoved, Location: "paths./pets.get.responses.404", Message: "response removed"},
+				{Breaking: false, Rule: ChangeResponseAdded, Location: "paths./pets.get.responses.500", Message: "response added"},
+			},
+		},
+		"optional parameter removed": {
+			old: document(pet(nil), []ParameterObject{limit}, responses("200")),
+			new: document(pet(nil), nil, responses("200")),
+			wantChanges: []Change{
+				{Breaking: true, Rule: ChangeParameterRemoved, Location: "paths./pets.get.parameters.query.limit", Message: "parameter removed"},
+			},
+		},
+		"exclusive bounds": {
+			old: document(pet(nil), []ParameterObject{
+				limit,
+				{Name: "offset", In: "query", Schema: &SchemaObject{Type: TypeInteger, Minimum: 0}},
+			}, responses("200")),
+			new: document(pet(nil), []ParameterObject{
+				{Name: "limit", In: "query", Schema: &SchemaObject{Type: TypeInteger, ExclusiveMaximum: 100}},
+				{Name: "offset", In: "query", Schema: &SchemaObject{Type: TypeInteger, Minimum: 0, ExclusiveMinimum: true}},
+			}, responses("200")),
+			wantChanges: []Change{
+				{Breaking: true, Rule: ChangeConstraintTightened, Location: "paths./pets.get.parameters.query.limit.schema.maximum", Message: "maximum changed from 100 to 100 exclusive"},
+				{Breaking: true, Rule: ChangeConstraintTightened, Location: "paths./pets.get.parameters.query.offset.schema.minimum", Message: "minimum changed from 0 to 0 exclusive"},
+			},
+		},
+		"schemas are compared as requests and responses": {
+			old: document(pet(nil), nil, responses("200")),
+			new: document(pet(func(pet *SchemaObject) {
+				pet.Required = []string{"name", "owner"}
+				pet.Properties = NewOrderedMap().
+					Set("name", &SchemaObject{Type: TypeString, MaxLength: 20}).
+					Set("kind", &SchemaObject{Type: TypeString, Enum: []interface{}{"cat", "bird"}}).
+					Set("owner", &SchemaObject{Type: TypeString})
+			}), nil, responses("200")),
+			wantChanges: []Change{
+				{Breaking: true, Rule: ChangePropertyRemoved, Location: "paths./pets.get.responses.200.content.application/json.schema.items.properties.age", Message: "property removed"},
+				{Breaking: false, Rule: ChangeEnumValuesRemoved, Location: "paths./pets.get.responses.200.content.application/json.schema.items.properties.kind.enum", Message: "enum values dog removed"},
+				{Breaking: true, Rule: ChangeEnumValuesAdded, Location: "paths./pets.get.responses.200.content.application/json.schema.items.properties.kind.enum", Message: "enum values bird added"},
+				{Breaking: false, Rule: ChangeConstraintTightened, Location: "paths./pets.get.responses.200.content.application/json.schema.items.properties.name.maxLength", Message: "maxLength changed from 50 to 20"},
+				{Breaking: false, Rule: ChangePropertyAdded, Location: "paths./pets.get.responses.200.content.application/json.schema.items.properties.owner", Message: "required property added"},
+				{Breaking: false, Rule: ChangePropertyRemoved, Location: "paths./pets.post.requestBody.content.application/json.schema.properties.age", Message: "property removed"},
+				{Breaking: true, Rule: ChangeEnumValuesRemoved, Location: "paths./pets.post.requestBody.content.application/json.schema.properties.kind.enum", Message: "enum values dog removed"},
+				{Breaking: false, Rule: ChangeEnumValuesAdded, Location: "paths./pets.post.requestBody.content.application/json.schema.properties.kind.enum", Message: "enum values bird added"},
+				{Breaking: true, Rule: ChangeConstraintTightened, Location: "paths./pets.post.requestBody.content.application/json.schema.properties.name.maxLength", Message: "maxLength changed from 50 to 20"},
+				{Breaking: true, Rule: ChangePropertyAdded, Location: "paths./pets.post.requestBody.content.application/json.schema.properties.owner", Message: "required property added"},
+			},
+		},
+		"type changes": {
+			old: document(pet(nil), nil, responses("200")),
+			new: document(pet(func(pet *SchemaObject) {
+				pet.Properties.Set("age", &SchemaObject{Type: TypeString})
+			}), nil, responses("200")),
+			wantChanges: []Change{
+				{Breaking: true, Rule: ChangeTypeChanged, Location: "paths./pets.get.responses.200.content.application/json.schema.items.properties.age.type", Message: "type changed from integer to string"},
+				{Breaking: true, Rule: ChangeTypeChanged, Location: "paths./pets.post.requestBody.content.application/json.schema.properties.age.type", Message: "type changed from integer to string"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.wantChanges, DiffOpenAPI(tc.old, tc.new))
+		})
+	}
+}
+
+func TestDiffOpenAPIComponents(t *testing.T) {
+	document := func(limit *SchemaObject, pets *SchemaObject) *OpenAPIObject {
+		return &OpenAPIObject{
+			Paths: PathsObject{
+				"/owners/{owner}/pets": {
+					Parameters: []ParameterObject{{Ref: "#/components/parameters/owner"}},
+					Get: &OperationObject{
+						Parameters: []ParameterObject{{Ref: "#/components/parameters/limit"}},
+						Responses:  ResponsesObject{"200": {Ref: "#/components/responses/pets"}},
+					},
+				},
+			},
+			Components: ComponentsObject{
+				Parameters: map[string]*ParameterObject{
+					"owner": {Name: "owner", In: InPath, Required: true, Schema: &SchemaObject{Type: TypeString}},
+					"limit": {Name: "limit", In: "query", Schema: limit},
+				},
+				Responses: map[string]*ResponseObject{
+					"pets": {Description: "Pets", Content: map[string]*MediaTypeObject{ContentTypeJSON: {Schema: *pets}}},
+				},
+			},
+		}
+	}
+
+	old := document(&SchemaObject{Type: TypeInteger, Maximum: 100}, &SchemaObject{Type: TypeArray, Items: &SchemaObject{Type: TypeString}})
+	new := document(&SchemaObject{Type: TypeInteger, Maximum: 50}, &SchemaObject{Type: TypeArray, Items: &SchemaObject{Type: TypeInteger}})
+	new.Components.Parameters["owner"] = &ParameterObject{Name: "owner", In: InPath, Required: true, Schema: &SchemaObject{Type: TypeInteger}}
+	assert.Equal(t, []Change{
+		{Breaking: true, Rule: ChangeConstraintTightened, Location: "paths./owners/{owner}/pets.get.parameters.query.limit.schema.maximum", Message: "maximum changed from 100 to 50"},
+		{Breaking: true, Rule: ChangeTypeChanged, Location: "paths./owners/{owner}/pets.get.parameters.path.owner.schema.type", Message: "type changed from string to integer"},
+		{Breaking: true, Rule: ChangeTypeChanged, Location: "paths./owners/{owner}/pets.get.responses.200.content.application/json.schema.items.type", Message: "type changed from string to integer"},
+	}, DiffOpenAPI(old, new))
+}
+
+func TestReadOpenAPIDocument(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "oas.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    get:
+      responses:
+        200:
+          description: Pets
+components:
+  parameters:
+    limit: {name: limit, in: query, schema: {type: integer}}
+  responses:
+    pets: {description: Pets}
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string, maxLength: 20}
+`), 0o644))
+
+	document, err := ReadOpenAPIDocument(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pets", document.Paths["/pets"].Get.Responses["200"].Description)
+	name := schemaProperties(document.Components.Schemas["Pet"])["name"]
+	assert.Equal(t, TypeString, name.Type)
+	assert.Equal(t, "limit", document.Components.Parameters["limit"].Name)
+	assert.Equal(t, "Pets", document.Components.Responses["pets"].Description)
+
+	swagger := filepath.Join(dir, "swagger.yaml")
+	assert.NoError(t, os.WriteFile(swagger, []byte(`swagger: "2.0"`), 0o644))
+	_, err = ReadOpenAPIDocument(swagger)
+	assert.Error(t, err)
+}
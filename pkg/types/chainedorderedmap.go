@@ -1,9 +1,8 @@
 package types
 
 import (
-	"encoding/json"
-
 	"github.com/iancoleman/orderedmap"
+	"gopkg.in/yaml.v2"
 )
 
 // ChainedOrderMap to enable chaining on orderedmap.OrderedMap
@@ -29,6 +28,11 @@ func (c *ChainedOrderedMap) Get(key string) (interface{}, bool) {
 	return c.m.Get(key)
 }
 
+// Keys in the order they were first set
+func (c *ChainedOrderedMap) Keys() []string {
+	return c.m.Keys()
+}
+
 // MarshalJSON pass through
 func (c *ChainedOrderedMap) MarshalJSON() ([]byte, error) {
 	return c.m.MarshalJSON()
@@ -39,28 +43,22 @@ func (c *ChainedOrderedMap) UnmarshalJSON(b []byte) error {
 	return c.m.UnmarshalJSON(b)
 }
 
-// MarshalYAML where orderedmap.OrderedMap cannot handle yaml marshaling
+// MarshalYAML where orderedmap.OrderedMap cannot handle yaml marshaling, the keys are kept in order
 func (c *ChainedOrderedMap) MarshalYAML() (interface{}, error) {
 	data, err := c.m.MarshalJSON()
 	if err != nil {
 		return nil, err
 	}
-
-	var in map[string]map[string]interface{}
-	if err := json.Unmarshal(data, &in); err != nil {
-		return nil, err
-	}
-
-	return in, nil
+	return jsonToYAML(data)
 }
 
 func (c *ChainedOrderedMap) UnmarshalYAML(unmarshal func(interface{}) error) error {
-	var in map[string]map[string]interface{}
+	var in yaml.MapSlice
 	if err := unmarshal(&in); err != nil {
 		return err
 	}
 
-	data, err := json.Marshal(in)
+	data, err := Document(in).MarshalJSON()
 	if err != nil {
 		return err
 	}
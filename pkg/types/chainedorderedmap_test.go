@@ -0,0 +1,48 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestChainedOrderedMap_YAML(t *testing.T) {
+	schema := SchemaObject{
+		Type: TypeObject,
+		Properties: NewOrderedMap().
+			Set("name", &SchemaObject{Type: TypeString}).
+			Set("id", &SchemaObject{Type: TypeInteger, Format: "int64"}).
+			Set("address", &SchemaObject{
+				Type:       TypeObject,
+				Properties: NewOrderedMap().Set("street", &SchemaObject{Type: TypeString}).Set("city", &SchemaObject{Type: TypeString}),
+			}),
+	}
+
+	expected := `type: object
+properties:
+  name:
+    type: string
+  id:
+    type: integer
+    format: int64
+  address:
+    type: object
+    properties:
+      street:
+        type: string
+      city:
+        type: string
+`
+	b, err := yaml.Marshal(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(b))
+
+	// properties keep their order when read back
+	var read SchemaObject
+	assert.NoError(t, yaml.Unmarshal(b, &read))
+	assert.Equal(t, []string{"name", "id", "address"}, read.Properties.Keys())
+	b, err = yaml.Marshal(read)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(b))
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// Diagnostic reported while generating a document, at the Position of the annotation or declaration it is caused by.
+// Code is a stable identifier of what is reported, and Severity is SeverityError or SeverityWarning.
+type Diagnostic struct {
+	Position token.Position `json:"position"`
+	Severity string         `json:"severity"`
+	Code     string         `json:"code"`
+	Message  string         `json:"message"`
+}
+
+// Error renders the diagnostic as file:line:column: message, diagnostics without a position as their message
+func (d Diagnostic) Error() string {
+	return d.located(d.Message)
+}
+
+// String renders the diagnostic as a line of a report, as file:line:column: severity: message (code)
+func (d Diagnostic) String() string {
+	return d.located(fmt.Sprintf("%s: %s (%s)", d.Severity, d.Message, d.Code))
+}
+
+func (d Diagnostic) located(text string) string {
+	if d.Position.Filename == "" && !d.Position.IsValid() {
+		return text
+	}
+	return fmt.Sprintf("%s: %s", d.Position, text)
+}
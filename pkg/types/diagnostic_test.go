@@ -0,0 +1,49 @@
+package types
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnostic(t *testing.T) {
+	tests := map[string]struct {
+		diagnostic Diagnostic
+		wantError  string
+		wantString string
+	}{
+		"located": {
+			diagnostic: Diagnostic{
+				Position: token.Position{Filename: "handler/pets.go", Line: 12, Column: 1},
+				Severity: SeverityError,
+				Code:     "parser.can-not-parse-comment",
+				Message:  "can not parse @Param comment",
+			},
+			wantError:  "handler/pets.go:12:1: can not parse @Param comment",
+			wantString: "handler/pets.go:12:1: error: can not parse @Param comment (parser.can-not-parse-comment)",
+		},
+		"file only": {
+			diagnostic: Diagnostic{
+				Position: token.Position{Filename: "main.go"},
+				Severity: SeverityError,
+				Code:     "parser.required-comment",
+				Message:  "info.title is required",
+			},
+			wantError:  "main.go: info.title is required",
+			wantString: "main.go: error: info.title is required (parser.required-comment)",
+		},
+		"not located": {
+			diagnostic: Diagnostic{Severity: SeverityWarning, Code: "swagger.unsupported", Message: "webhooks are dropped"},
+			wantError:  "webhooks are dropped",
+			wantString: "warning: webhooks are dropped (swagger.unsupported)",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, tc.diagnostic, tc.wantError)
+			assert.Equal(t, tc.wantString, tc.diagnostic.String())
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DifferenceAdded   = "added"
+	DifferenceRemoved = "removed"
+	DifferenceChanged = "changed"
+)
+
+// Difference between two documents at Pointer, the json pointer of the value that differs. Old is not set for added
+// values and New for removed ones.
+type Difference struct {
+	Kind    string
+	Pointer string
+	Old     interface{}
+	New     interface{}
+}
+
+// String renders the difference as a line of a diff
+func (d Difference) String() string {
+	switch d.Kind {
+	case DifferenceAdded:
+		return fmt.Sprintf("+ %s: %s", d.Pointer, diffValue(d.New))
+	case DifferenceRemoved:
+		return fmt.Sprintf("- %s: %s", d.Pointer, diffValue(d.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", d.Pointer, diffValue(d.Old), diffValue(d.New))
+}
+
+// maxDiffValueLength is the length values are truncated to in a diff
+const maxDiffValueLength = 80
+
+func diffValue(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if s := string(b); len(s) > maxDiffValueLength {
+		return s[:maxDiffValueLength-3] + "..."
+	}
+	return string(b)
+}
+
+// DecodeDocument decodes a json or yaml document into maps, slices and values, to be compared with DiffDocuments
+// regardless of how it is formatted
+func DecodeDocument(data []byte) (interface{}, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return yamlToJSON(document), nil
+}
+
+// DiffDocuments lists the differences between two decoded documents, sorted by their pointer. Objects are compared
+// key by key and arrays index by index.
+func DiffDocuments(old, new interface{}) []Difference {
+	var differences []Difference
+	diffValues("", old, new, &differences)
+	sort.SliceStable(differences, func(i, j int) bool {
+		return differences[i].Pointer < differences[j].Pointer
+	})
+	return differences
+}
+
+func diffValues(pointer string, old, new interface{}, differences *[]Difference) {
+	switch o := old.(type) {
+	case map[string]interface{}:
+		n, ok := new.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(o) {
+			keyPointer := pointer + "/" + escapePointer(key)
+			if value, ok := n[key]; ok {
+				diffValues(keyPointer, o[key], value, differences)
+			} else {
+				*differences = append(*differences, Difference{Kind: DifferenceRemoved, Pointer: keyPointer, Old: o[key]})
+			}
+		}
+		for _, key := range sortedKeys(n) {
+			if _, ok := o[key]; !ok {
+				*differences = append(*differences, Difference{Kind: DifferenceAdded, Pointer: pointer + "/" + escapePointer(key), New: n[key]})
+			}
+		}
+		return
+	case []interface{}:
+		n, ok := new.([]interface{})
+		if !ok {
+			break
+		}
+		for i := range o {
+			indexPointer := pointer + "/" + strconv.Itoa(i)
+			if i < len(n) {
+				diffValues(indexPointer, o[i], n[i], differences)
+			} else {
+				*differences = append(*differences, Difference{Kind: DifferenceRemoved, Pointer: indexPointer, Old: o[i]})
+			}
+		}
+		for i := len(o); i < len(n); i++ {
+			*differences = append(*differences, Difference{Kind: DifferenceAdded, Pointer: pointer + "/" + strconv.Itoa(i), New: n[i]})
+		}
+		return
+	}
+	if !reflect.DeepEqual(old, new) {
+		*differences = append(*differences, Difference{Kind: DifferenceChanged, Pointer: pointer, Old: old, New: new})
+	}
+}
+
+// JSONPointer joins reference tokens into a json pointer, escaping them
+func JSONPointer(tokens ...string) string {
+	var pointer strings.Builder
+	for _, token := range tokens {
+		pointer.WriteString("/" + escapePointer(token))
+	}
+	return pointer.String()
+}
+
+// escapePointer escapes a key as a json pointer reference token
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffDocuments(t *testing.T) {
+	old, err := DecodeDocument([]byte(`
+paths:
+  /pets/{id}:
+    get:
+      summary: Get a pet
+      tags: [pets, store]
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id]
+`))
+	assert.NoError(t, err)
+	new, err := DecodeDocument([]byte(`{
+		"components": {"schemas": {"Pet": {"type": "object"}, "Owner": {"type": "object"}}},
+		"paths": {"/pets/{id}": {"get": {"summary": "Get a pet by id", "tags": ["pets", "store", "owners"]}}}
+	}`))
+	assert.NoError(t, err)
+
+	differences := DiffDocuments(old, new)
+	assert.Equal(t, []Difference{
+		{Kind: DifferenceAdded, Pointer: "/components/schemas/Owner", New: map[string]interface{}{"type": "object"}},
+		{Kind: DifferenceRemoved, Pointer: "/components/schemas/Pet/required", Old: []interface{}{"id"}},
+		{Kind: DifferenceChanged, Pointer: "/paths/~1pets~1{id}/get/summary", Old: "Get a pet", New: "Get a pet by id"},
+		{Kind: DifferenceAdded, Pointer: "/paths/~1pets~1{id}/get/tags/2", New: "owners"},
+	}, differences)
+
+	var lines []string
+	for _, difference := range differences {
+		lines = append(lines, difference.String())
+	}
+	assert.Equal(t, []string{
+		`+ /components/schemas/Owner: {"type":"object"}`,
+		`- /components/schemas/Pet/required: ["id"]`,
+		`~ /paths/~1pets~1{id}/get/summary: "Get a pet" -> "Get a pet by id"`,
+		`+ /paths/~1pets~1{id}/get/tags/2: "owners"`,
+	}, lines)
+
+	assert.Empty(t, DiffDocuments(old, old))
+}
@@ -0,0 +1,156 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Document is an untyped OpenAPI or Swagger document, its objects are yaml.MapSlice to keep the order of their keys
+type Document yaml.MapSlice
+
+// NewDocument converts a document into an untyped Document, its keys in the order they are marshalled to json
+func NewDocument(document interface{}) (Document, error) {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := jsonToYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := decoded.(yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("document is not an object")
+	}
+	return Document(root), nil
+}
+
+// OrderKeys orders the entries of the object under key by keys, entries that are not in keys are kept after them
+func (d Document) OrderKeys(key string, keys []string) {
+	object, ok := getPath(yaml.MapSlice(d), []string{key})
+	if !ok {
+		return
+	}
+	position := make(map[string]int, len(keys))
+	for i, k := range keys {
+		if _, ok := position[k]; !ok {
+			position[k] = i
+		}
+	}
+	rank := func(item yaml.MapItem) int {
+		if i, ok := position[fmt.Sprint(item.Key)]; ok {
+			return i
+		}
+		return len(keys)
+	}
+	sort.SliceStable(object, func(i, j int) bool {
+		return rank(object[i]) < rank(object[j])
+	})
+}
+
+// MarshalJSON marshals the objects of the document in order
+func (d Document) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeJSONValue(&buf, yaml.MapSlice(d)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML marshals the document as the yaml.MapSlice it is
+func (d Document) MarshalYAML() (interface{}, error) {
+	return yaml.MapSlice(d), nil
+}
+
+func encodeJSONValue(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		buf.WriteByte('{')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(fmt.Sprint(item.Key))
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := encodeJSONValue(buf, item.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeJSONValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case map[interface{}]interface{}:
+		return encodeJSONValue(buf, yamlToJSON(v))
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+func asMapSlice(value interface{}) yaml.MapSlice {
+	object, _ := value.(yaml.MapSlice)
+	return object
+}
+
+func getKey(object yaml.MapSlice, key string) (interface{}, bool) {
+	for _, item := range object {
+		if fmt.Sprint(item.Key) == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+func hasKey(object yaml.MapSlice, key string) bool {
+	_, ok := getKey(object, key)
+	return ok
+}
+
+// getPath returns the object nested in object under keys
+func getPath(object yaml.MapSlice, keys []string) (yaml.MapSlice, bool) {
+	for _, key := range keys {
+		value, ok := getKey(object, key)
+		if !ok {
+			return nil, false
+		}
+		if object, ok = value.(yaml.MapSlice); !ok {
+			return nil, false
+		}
+	}
+	return object, true
+}
+
+// setPath sets value nested in object under keys, adding the objects that are missing
+func setPath(object yaml.MapSlice, keys []string, value interface{}) yaml.MapSlice {
+	key := keys[0]
+	if len(keys) > 1 {
+		nested, _ := getKey(object, key)
+		value = setPath(asMapSlice(nested), keys[1:], value)
+	}
+	for i, item := range object {
+		if fmt.Sprint(item.Key) == key {
+			object[i].Value = value
+			return object
+		}
+	}
+	return append(object, yaml.MapItem{Key: key, Value: value})
+}
@@ -0,0 +1,295 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityOff     = "off"
+)
+
+// Severities a lint rule can be configured with
+var Severities = []string{SeverityError, SeverityWarning, SeverityInfo, SeverityOff}
+
+const (
+	RuleOperationID         = "operation-id"
+	RuleOperationIDCasing   = "operation-id-casing"
+	RuleOperationSummary    = "operation-summary"
+	RuleOperationTags       = "operation-tags"
+	RuleErrorResponses      = "error-responses"
+	RuleUndeclaredTag       = "undeclared-tag"
+	RulePathCasing          = "path-casing"
+	RuleSchemaDescription   = "schema-description"
+	RulePropertyDescription = "property-description"
+	RuleUnusedSchema        = "unused-schema"
+)
+
+// LintRule checks the style of a document. Rules report at their default Severity unless configured otherwise.
+type LintRule struct {
+	ID          string
+	Description string
+	Severity    string
+
+	check func(o *OpenAPIObject, report func(pointer, message string, args ...interface{}))
+}
+
+// LintResult of a rule at Pointer, the json pointer of the value the rule reports
+type LintResult struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Pointer  string `json:"pointer"`
+	Message  string `json:"message"`
+}
+
+// String renders the result as a line of a report
+func (r LintResult) String() string {
+	return fmt.Sprintf("%-8s %s: %s (%s)", r.Severity, r.Pointer, r.Message, r.Rule)
+}
+
+// LintRules of goas, in the order they are documented
+var LintRules = []LintRule{
+	{ID: RuleOperationID, Description: "operations have an operationId, set with @ID", Severity: SeverityWarning, check: lintOperationID},
+	{ID: RuleOperationIDCasing, Description: "operationIds are camelCase", Severity: SeverityWarning, check: lintOperationIDCasing},
+	{ID: RuleOperationSummary, Description: "operations have a summary, set with @Title", Severity: SeverityWarning, check: lintOperationSummary},
+	{ID: RuleOperationTags, Description: "operations have a tag, set with @Tag or @Resource", Severity: SeverityWarning, check: lintOperationTags},
+	{ID: RuleErrorResponses, Description: "operations document a 4xx, 5xx or default response", Severity: SeverityWarning, check: lintErrorResponses},
+	{ID: RuleUndeclaredTag, Description: "tags of operations are declared with @Tag in the main file", Severity: SeverityWarning, check: lintUndeclaredTag},
+	{ID: RulePathCasing, Description: "the segments of paths share the same casing", Severity: SeverityWarning, check: lintPathCasing},
+	{ID: RuleSchemaDescription, Description: "component schemas have a description", Severity: SeverityInfo, check: lintSchemaDescription},
+	{ID: RulePropertyDescription, Description: "properties of component schemas have a description", Severity: SeverityInfo, check: lintPropertyDescription},
+	{ID: RuleUnusedSchema, Description: "component schemas are used by an operation", Severity: SeverityWarning, check: lintUnusedSchema},
+}
+
+// Lint checks a document with every lint rule, at the severities rules are configured with by their id. Rules
+// configured as off are skipped. Results are sorted by their pointer.
+func Lint(o *OpenAPIObject, severities map[string]string) ([]LintResult, error) {
+	known := map[string]bool{}
+	for _, rule := range LintRules {
+		known[rule.ID] = true
+	}
+	for _, id := range sortedKeys(severities) {
+		if !known[id] {
+			return nil, fmt.Errorf("unknown lint rule %s", id)
+		}
+		if !isSeverity(severities[id]) {
+			return nil, fmt.Errorf("unknown severity %s of lint rule %s, expected one of %s", severities[id], id, strings.Join(Severities, ", "))
+		}
+	}
+
+	var results []LintResult
+	for _, rule := range LintRules {
+		severity := rule.Severity
+		if configured, ok := severities[rule.ID]; ok {
+			severity = configured
+		}
+		if severity == SeverityOff {
+			continue
+		}
+		rule.check(o, func(pointer, message string, args ...interface{}) {
+			results = append(results, LintResult{Rule: rule.ID, Severity: severity, Pointer: pointer, Message: fmt.Sprintf(message, args...)})
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Pointer < results[j].Pointer
+	})
+	return results, nil
+}
+
+func isSeverity(severity string) bool {
+	for _, s := range Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// eachOperation calls fn for every operation of the paths and webhooks of a document, with its json pointer
+func eachOperation(o *OpenAPIObject, fn func(pointer string, operation *OperationObject)) {
+	for section, paths := range map[string]PathsObject{"paths": o.Paths, "webhooks": o.Webhooks} {
+		for _, path := range sortedKeys(paths) {
+			operations := pathItemOperations(paths[path])
+			for _, method := range operationMethods {
+				if operation := operations[method]; operation != nil {
+					fn(JSONPointer(section, path, method), operation)
+				}
+			}
+		}
+	}
+}
+
+func lintOperationID(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	eachOperation(o, func(pointer string, operation *OperationObject) {
+		if operation.OperationID == "" {
+			report(pointer, "operation has no operationId")
+		}
+	})
+}
+
+var camelCase = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+func lintOperationIDCasing(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	eachOperation(o, func(pointer string, operation *OperationObject) {
+		if operation.OperationID != "" && !camelCase.MatchString(operation.OperationID) {
+			report(pointer+"/operationId", "operationId %s is not camelCase", operation.OperationID)
+		}
+	})
+}
+
+func lintOperationSummary(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	eachOperation(o, func(pointer string, operation *OperationObject) {
+		if operation.Summary == "" {
+			report(pointer, "operation has no summary")
+		}
+	})
+}
+
+func lintOperationTags(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	eachOperation(o, func(pointer string, operation *OperationObject) {
+		if len(operation.Tags) == 0 {
+			report(pointer, "operation has no tags")
+		}
+	})
+}
+
+func lintErrorResponses(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	eachOperation(o, func(pointer string, operation *OperationObject) {
+		for status := range operation.Responses {
+			if status == "default" || strings.HasPrefix(status, "4") || strings.HasPrefix(status, "5") {
+				return
+			}
+		}
+		report(pointer+"/responses", "operation documents no 4xx or 5xx response")
+	})
+}
+
+func lintUndeclaredTag(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	declared := map[string]bool{}
+	for _, tag := range o.Tags {
+		declared[tag.Name] = true
+	}
+	eachOperation(o, func(pointer string, operation *OperationObject) {
+		for i, tag := range operation.Tags {
+			if !declared[tag] {
+				report(pointer+"/tags/"+strconv.Itoa(i), "tag %s is not declared", tag)
+			}
+		}
+	})
+}
+
+// pathCasings in the order they are preferred in, when paths use as many segments of each
+var pathCasings = []string{"kebab", "snake", "camel"}
+
+// pathCasing of a static path segment, segments of a single lower case word have none
+func pathCasing(segment string) string {
+	switch {
+	case strings.Contains(segment, "-"):
+		return "kebab"
+	case strings.Contains(segment, "_"):
+		return "snake"
+	case strings.ToLower(segment) != segment:
+		return "camel"
+	}
+	return ""
+}
+
+func lintPathCasing(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	counts := map[string]int{}
+	for path := range o.Paths {
+		for _, segment := range strings.Split(path, "/") {
+			if !strings.HasPrefix(segment, "{") {
+				counts[pathCasing(segment)]++
+			}
+		}
+	}
+	casing, most := "", 0
+	for _, c := range pathCasings {
+		if counts[c] > most {
+			casing, most = c, counts[c]
+		}
+	}
+	if casing == "" {
+		return
+	}
+
+	for _, path := range sortedKeys(o.Paths) {
+		for _, segment := range strings.Split(path, "/") {
+			if c := pathCasing(segment); c != "" && c != casing && !strings.HasPrefix(segment, "{") {
+				report(JSONPointer("paths", path), "path segment %s is %s case, paths mostly use %s case", segment, c, casing)
+				break
+			}
+		}
+	}
+}
+
+func lintSchemaDescription(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	for _, name := range sortedKeys(o.Components.Schemas) {
+		if schema := o.Components.Schemas[name]; schema != nil && schema.Ref == "" && schema.Description == "" {
+			report(JSONPointer("components", "schemas", name), "schema has no description")
+		}
+	}
+}
+
+func lintPropertyDescription(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	var lintProperties func(pointer string, schema *SchemaObject)
+	lintProperties = func(pointer string, schema *SchemaObject) {
+		if schema == nil {
+			return
+		}
+		lintProperties(pointer+"/items", schema.Items)
+		if schema.Properties == nil {
+			return
+		}
+		for _, key := range schema.Properties.Keys() {
+			value, _ := schema.Properties.Get(key)
+			property, ok := value.(*SchemaObject)
+			if !ok {
+				continue
+			}
+			propertyPointer := pointer + JSONPointer("properties", key)
+			if property.Ref == "" && property.Description == "" {
+				report(propertyPointer, "property has no description")
+			}
+			lintProperties(propertyPointer, property)
+		}
+	}
+	for _, name := range sortedKeys(o.Components.Schemas) {
+		lintProperties(JSONPointer("components", "schemas", name), o.Components.Schemas[name])
+	}
+}
+
+func lintUnusedSchema(o *OpenAPIObject, report func(pointer, message string, args ...interface{})) {
+	used := map[string]bool{}
+	var pending []string
+	visited := map[*SchemaObject]bool{}
+	use := func(schema *SchemaObject) {
+		walkSchema(schema, visited, func(schema *SchemaObject) {
+			name := strings.TrimPrefix(schema.Ref, refPrefixSchemas)
+			if name != schema.Ref && !used[name] {
+				used[name] = true
+				pending = append(pending, name)
+			}
+		})
+	}
+	eachOperation(o, func(pointer string, operation *OperationObject) {
+		walkOperation(operation, use)
+	})
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		use(o.Components.Schemas[name])
+	}
+
+	for _, name := range sortedKeys(o.Components.Schemas) {
+		if !used[name] {
+			report(JSONPointer("components", "schemas", name), "schema is not used by any operation")
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	document := func() *OpenAPIObject {
+		return &OpenAPIObject{
+			Tags: []TagObject{{Name: "pets"}},
+			Paths: PathsObject{
+				"/pets": {
+					Get: &OperationObject{
+						OperationID: "listPets",
+						Summary:     "List pets",
+						Tags:        []string{"pets"},
+						Responses: ResponsesObject{
+							"200":     {Description: "Pets", Content: map[string]*MediaTypeObject{ContentTypeJSON: {Schema: SchemaObject{Ref: "#/components/schemas/Pets"}}}},
+							"default": {Description: "Error"},
+						},
+					},
+				},
+				"/pet-owners": {
+					Get: &OperationObject{
+						OperationID: "ListOwners",
+						Tags:        []string{"owners"},
+						Responses:   ResponsesObject{"200": {Description: "Owners"}},
+					},
+				},
+				"/pet_sitters": {
+					Get: &OperationObject{Responses: ResponsesObject{"200": {Description: "Sitters"}}},
+				},
+			},
+			Components: ComponentsObject{Schemas: map[string]*SchemaObject{
+				"Pets": {Type: TypeArray, Description: "Pets", Items: &SchemaObject{Ref: "#/components/schemas/Pet"}},
+				"Pet": {Type: TypeObject, Description: "A pet", Properties: NewOrderedMap().
+					Set("name", &SchemaObject{Type: TypeString, Description: "Name of the pet"}).
+					Set("owner", &SchemaObject{Type: TypeObject, Properties: NewOrderedMap().
+						Set("name", &SchemaObject{Type: TypeString})})},
+				"Owner": {Type: TypeObject},
+			}},
+		}
+	}
+
+	tests := map[string]struct {
+		severities  map[string]string
+		wantResults []LintResult
+		wantErr     bool
+	}{
+		"default severities": {
+			wantResults: []LintResult{
+				{Rule: RuleSchemaDescription, Severity: SeverityInfo, Pointer: "/components/schemas/Owner", Message: "schema has no description"},
+				{Rule: RuleUnusedSchema, Severity: SeverityWarning, Pointer: "/components/schemas/Owner", Message: "schema is not used by any operation"},
+				{Rule: RulePropertyDescription, Severity: SeverityInfo, Pointer: "/components/schemas/Pet/properties/owner", Message: "property has no description"},
+				{Rule: RulePropertyDescription, Severity: SeverityInfo, Pointer: "/components/schemas/Pet/properties/owner/properties/name", Message: "property has no description"},
+				{Rule: RuleOperationSummary, Severity: SeverityWarning, Pointer: "/paths/~1pet-owners/get", Message: "operation has no summary"},
+				{Rule: RuleOperationIDCasing, Severity: SeverityWarning, Pointer: "/paths/~1pet-owners/get/operationId", Message: "operationId ListOwners is not camelCase"},
+				{Rule: RuleErrorResponses, Severity: SeverityWarning, Pointer: "/paths/~1pet-owners/get/responses", Message: "operation documents no 4xx or 5xx response"},
+				{Rule: RuleUndeclaredTag, Severity: SeverityWarning, Pointer: "/paths/~1pet-owners/get/tags/0", Message: "tag owners is not declared"},
+				{Rule: RulePathCasing, Severity: SeverityWarning, Pointer: "/paths/~1pet_sitters", Message: "path segment pet_sitters is snake case, paths mostly use kebab case"},
+				{Rule: RuleOperationID, Severity: SeverityWarning, Pointer: "/paths/~1pet_sitters/get", Message: "operation has no operationId"},
+				{Rule: RuleOperationSummary, Severity: SeverityWarning, Pointer: "/paths/~1pet_sitters/get", Message: "operation has no summary"},
+				{Rule: RuleOperationTags, Severity: SeverityWarning, Pointer: "/paths/~1pet_sitters/get", Message: "operation has no tags"},
+				{Rule: RuleErrorResponses, Severity: SeverityWarning, Pointer: "/paths/~1pet_sitters/get/responses", Message: "operation documents no 4xx or 5xx response"},
+			},
+		},
+		"configured severities": {
+			severities: map[string]string{
+				RuleOperationID:         SeverityOff,
+				RuleOperationSummary:    SeverityOff,
+				RuleOperationTags:       SeverityOff,
+				RuleErrorResponses:      SeverityOff,
+				RuleUndeclaredTag:       SeverityOff,
+				RulePathCasing:          SeverityOff,
+				RuleSchemaDescription:   SeverityOff,
+				RulePropertyDescription: SeverityOff,
+				RuleOperationIDCasing:   SeverityError,
+			},
+			wantResults: []LintResult{
+				{Rule: RuleUnusedSchema, Severity: SeverityWarning, Pointer: "/components/schemas/Owner", Message: "schema is not used by any operation"},
+				{Rule: RuleOperationIDCasing, Severity: SeverityError, Pointer: "/paths/~1pet-owners/get/operationId", Message: "operationId ListOwners is not camelCase"},
+			},
+		},
+		"unknown rule": {
+			severities: map[string]string{"operation-title": SeverityOff},
+			wantErr:    true,
+		},
+		"unknown severity": {
+			severities: map[string]string{RuleOperationID: "fatal"},
+			wantErr:    true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			results, err := Lint(document(), tc.severities)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantResults, results)
+		})
+	}
+}
+
+func TestLintSARIF(t *testing.T) {
+	results := []LintResult{
+		{Rule: RuleOperationID, Severity: SeverityWarning, Pointer: "/paths/~1pets/get", Message: "operation has no operationId"},
+		{Rule: RuleSchemaDescription, Severity: SeverityInfo, Pointer: "/components/schemas/Pet", Message: "schema has no description"},
+	}
+	log := LintSARIF(results, func(pointer string) token.Position {
+		if pointer == "/paths/~1pets/get" {
+			return token.Position{Filename: "handler/pets.go", Line: 12, Column: 1}
+		}
+		return token.Position{}
+	})
+
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs, 1)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, len(LintRules))
+	assert.Equal(t, []SARIFResult{
+		{
+			RuleID:  RuleOperationID,
+			Level:   "warning",
+			Message: SARIFMessage{Text: "operation has no operationId"},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: &SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{URI: "handler/pets.go"},
+					Region:           &SARIFRegion{StartLine: 12, StartColumn: 1},
+				},
+				LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: "/paths/~1pets/get", Kind: "object"}},
+			}},
+		},
+		{
+			RuleID:    RuleSchemaDescription,
+			Level:     "note",
+			Message:   SARIFMessage{Text: "schema has no description"},
+			Locations: []SARIFLocation{{LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: "/components/schemas/Pet", Kind: "object"}}}},
+		},
+	}, log.Runs[0].Results)
+}
@@ -0,0 +1,39 @@
+package types
+
+// TypeMapping overrides the schema of a Go type, mapped types are keyed by their import path and name,
+// such as github.com/google/uuid.UUID
+type TypeMapping struct {
+	Type     string      `json:"type,omitempty" yaml:"type,omitempty"`
+	Format   string      `json:"format,omitempty" yaml:"format,omitempty"`
+	Pattern  string      `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Nullable bool        `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	Example  interface{} `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+// Schema documenting the mapped type
+func (m TypeMapping) Schema() *SchemaObject {
+	return &SchemaObject{
+		Type:     m.Type,
+		Format:   m.Format,
+		Pattern:  m.Pattern,
+		Nullable: m.Nullable,
+		Example:  m.Example,
+	}
+}
+
+// DefaultTypeMappings of standard library and common ecosystem types, as encoding/json serialises them
+var DefaultTypeMappings = map[string]TypeMapping{
+	GoTypeTime:                                  {Type: TypeString, Format: "date-time"},
+	"time.Duration":                             {Type: TypeInteger, Format: "int64"},
+	"encoding/json.RawMessage":                  {},
+	"net.IP":                                    {Type: TypeString},
+	"net/netip.Addr":                            {Type: TypeString},
+	"net/netip.Prefix":                          {Type: TypeString},
+	"math/big.Int":                              {Type: TypeInteger},
+	"math/big.Float":                            {Type: TypeString},
+	"github.com/google/uuid.UUID":               {Type: TypeString, Format: "uuid"},
+	"github.com/gofrs/uuid.UUID":                {Type: TypeString, Format: "uuid"},
+	"github.com/satori/go.uuid.UUID":            {Type: TypeString, Format: "uuid"},
+	"github.com/shopspring/decimal.Decimal":     {Type: TypeString, Format: "decimal"},
+	"github.com/shopspring/decimal.NullDecimal": {Type: TypeString, Format: "decimal", Nullable: true},
+}
@@ -1,19 +1,50 @@
 package types
 
-// GoTypesOASFormats conversion map
+const (
+	FormatByte = "byte"
+)
+
+// GoTypesOASFormats conversion map, formats are as wide as the go type. Unsigned types are documented as int64,
+// with a minimum of 0, as there is no unsigned format
 var GoTypesOASFormats = map[string]string{
-	"bool":    "boolean",
 	"uint":    "int64",
 	"uint8":   "int64",
 	"uint16":  "int64",
 	"uint32":  "int64",
 	"uint64":  "int64",
+	"byte":    "int64",
 	"int":     "int64",
-	"int8":    "int64",
-	"int16":   "int64",
-	"int32":   "int64",
+	"int8":    "int32",
+	"int16":   "int32",
+	"int32":   "int32",
+	"rune":    "int32",
 	"int64":   "int64",
 	"float32": "float",
 	"float64": "double",
-	"string":  "string",
+}
+
+var unsignedGoTypes = map[string]bool{
+	"uint":   true,
+	"uint8":  true,
+	"uint16": true,
+	"uint32": true,
+	"uint64": true,
+	"byte":   true,
+}
+
+// GoTypeOASSchema returns the schema of a basic go type
+func GoTypeOASSchema(goType string) *SchemaObject {
+	schema := &SchemaObject{
+		Type:   GoTypesOASTypes[goType],
+		Format: GoTypesOASFormats[goType],
+	}
+	if unsignedGoTypes[goType] {
+		schema.Minimum = 0
+	}
+	return schema
+}
+
+// IsGoTypeByte checks if a typename is a byte, slices of which are serialised as base64 encoded strings
+func IsGoTypeByte(typeName string) bool {
+	return typeName == "byte" || typeName == "uint8"
 }
@@ -1,7 +1,8 @@
 package types
 
 const (
-	OpenAPIVersion = "3.0.0"
+	OpenAPIVersion   = "3.0.0"
+	OpenAPIVersion31 = "3.1.0"
 
 	ContentTypeText = "text/plain"
 	ContentTypeJSON = "application/json"
@@ -15,8 +16,11 @@ const (
 	AttributeContactEmail = "@contactemail"
 	AttributeContactURL   = "@contacturl"
 
-	AttributeLicenseName = "@licensename"
-	AttributeLicenseURL  = "@licenseurl"
+	AttributeSummary = "@summary"
+
+	AttributeLicenseName       = "@licensename"
+	AttributeLicenseURL        = "@licenseurl"
+	AttributeLicenseIdentifier = "@licenseidentifier"
 
 	AttributeServer         = "@server"
 	AttributeServerVariable = "@servervariable"
@@ -37,9 +41,12 @@ const (
 
 	AttributeID = "@id"
 
+	AttributeSchema = "@schema"
+
 	AttributeResource = "@resource"
 	AttributeRoute    = "@route"
 	AttributeRouter   = "@router"
+	AttributeWebhook  = "@webhook"
 
 	KeywordRequired = "required"
 
@@ -49,11 +56,13 @@ const (
 	InBody  = "body"
 	InPath  = "path"
 
+	TypeString  = "string"
 	TypeBoolean = "boolean"
 	TypeInteger = "integer"
 	TypeNumber  = "number"
 	TypeObject  = "object"
 	TypeArray   = "array"
+	TypeNull    = "null"
 
 	DefaultFieldName = "key"
 
@@ -70,11 +79,13 @@ var GoTypesOASTypes = map[string]string{
 	"uint16":  "integer",
 	"uint32":  "integer",
 	"uint64":  "integer",
+	"byte":    "integer",
 	"int":     "integer",
 	"int8":    "integer",
 	"int16":   "integer",
 	"int32":   "integer",
 	"int64":   "integer",
+	"rune":    "integer",
 	"float32": "number",
 	"float64": "number",
 	"string":  "string",
@@ -97,6 +108,8 @@ type OpenAPIObject struct {
 
 	Tags         []TagObject                  `json:"tags,omitempty" yaml:",omitempty"`
 	ExternalDocs *ExternalDocumentationObject `json:"externalDocs,omitempty" yaml:",omitempty"`
+
+	Webhooks PathsObject `json:"webhooks,omitempty" yaml:",omitempty"` // OpenAPI 3.1
 }
 
 type ServerObject struct {
@@ -114,6 +127,7 @@ type ServerVariableObject struct {
 
 type InfoObject struct {
 	Title          string         `json:"title" yaml:"title"`
+	Summary        string         `json:"summary,omitempty" yaml:",omitempty"` // OpenAPI 3.1
 	Description    string         `json:"description,omitempty" yaml:",omitempty"`
 	TermsOfService string         `json:"termsOfService,omitempty" yaml:",omitempty"`
 	Contact        *ContactObject `json:"contact,omitempty" yaml:",omitempty"`
@@ -128,27 +142,28 @@ type ContactObject struct {
 }
 
 type LicenseObject struct {
-	Name string `json:"name,omitempty" yaml:",omitempty"`
-	URL  string `json:"url,omitempty" yaml:",omitempty"`
+	Name       string `json:"name,omitempty" yaml:",omitempty"`
+	URL        string `json:"url,omitempty" yaml:",omitempty"`
+	Identifier string `json:"identifier,omitempty" yaml:",omitempty"` // OpenAPI 3.1
 }
 
 type PathsObject map[string]*PathItemObject
 
 type PathItemObject struct {
-	Ref         string           `json:"$ref,omitempty" yaml:"$ref,omitempty"`
-	Summary     string           `json:"summary,omitempty" yaml:",omitempty"`
-	Description string           `json:"description,omitempty" yaml:",omitempty"`
-	Get         *OperationObject `json:"get,omitempty" yaml:",omitempty"`
-	Post        *OperationObject `json:"post,omitempty" yaml:",omitempty"`
-	Patch       *OperationObject `json:"patch,omitempty" yaml:",omitempty"`
-	Put         *OperationObject `json:"put,omitempty" yaml:",omitempty"`
-	Delete      *OperationObject `json:"delete,omitempty" yaml:",omitempty"`
-	Options     *OperationObject `json:"options,omitempty" yaml:",omitempty"`
-	Head        *OperationObject `json:"head,omitempty" yaml:",omitempty"`
-	Trace       *OperationObject `json:"trace,omitempty" yaml:",omitempty"`
+	Ref         string            `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Summary     string            `json:"summary,omitempty" yaml:",omitempty"`
+	Description string            `json:"description,omitempty" yaml:",omitempty"`
+	Get         *OperationObject  `json:"get,omitempty" yaml:",omitempty"`
+	Post        *OperationObject  `json:"post,omitempty" yaml:",omitempty"`
+	Patch       *OperationObject  `json:"patch,omitempty" yaml:",omitempty"`
+	Put         *OperationObject  `json:"put,omitempty" yaml:",omitempty"`
+	Delete      *OperationObject  `json:"delete,omitempty" yaml:",omitempty"`
+	Options     *OperationObject  `json:"options,omitempty" yaml:",omitempty"`
+	Head        *OperationObject  `json:"head,omitempty" yaml:",omitempty"`
+	Trace       *OperationObject  `json:"trace,omitempty" yaml:",omitempty"`
+	Parameters  []ParameterObject `json:"parameters,omitempty" yaml:",omitempty"`
 
 	// Servers
-	// Parameters
 }
 
 type OperationObject struct {
@@ -183,11 +198,12 @@ type ParameterObject struct {
 
 	Deprecated      bool `json:"deprecated,omitempty" yaml:",omitempty"`
 	AllowEmptyValue bool `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	// Content describes the parameter instead of Schema, with a single media type
+	Content map[string]*MediaTypeObject `json:"content,omitempty" yaml:",omitempty"`
 	// Style
 	// Explode
 	// AllowReserved
 	// Examples
-	// Content
 }
 
 type ReferenceObject struct {
@@ -219,41 +235,47 @@ type SchemaObject struct {
 	DisabledFieldNames map[string]struct{} `json:"-" yaml:"-"` // For goas
 
 	Type         string                       `json:"type,omitempty" yaml:",omitempty"`
+	Types        []string                     `json:"-" yaml:"-"` // Marshalled as the type of schemas of more than one type
 	Format       string                       `json:"format,omitempty" yaml:",omitempty"`
 	Required     []string                     `json:"required,omitempty" yaml:",omitempty"`
 	Properties   *ChainedOrderedMap           `json:"properties,omitempty" yaml:",omitempty"`
 	Description  string                       `json:"description,omitempty" yaml:",omitempty"`
 	Items        *SchemaObject                `json:"items,omitempty" yaml:",omitempty"` // use ptr to prevent recursive error
 	Example      interface{}                  `json:"example,omitempty" yaml:",omitempty"`
+	Examples     []interface{}                `json:"examples,omitempty" yaml:",omitempty"` // OpenAPI 3.1
 	Deprecated   bool                         `json:"deprecated,omitempty" yaml:",omitempty"`
 	ExternalDocs *ExternalDocumentationObject `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
 
 	Title string `json:"title,omitempty" yaml:",omitempty"`
 
-	MultipleOf           interface{}        `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
-	Minimum              interface{}        `json:"minimum,omitempty" yaml:",omitempty"`
-	Maximum              interface{}        `json:"maximum,omitempty" yaml:",omitempty"`
-	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
-	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
-	MaxLength            interface{}        `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
-	MinLength            interface{}        `json:"minLength,omitempty" yaml:"minLength,omitempty"`
-	Pattern              string             `json:"pattern,omitempty" yaml:",omitempty"`
-	MaxItems             int                `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
-	MinItems             int                `json:"minItems,omitempty" yaml:"minItems,omitempty"`
-	UniqueItems          bool               `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
-	MaxProperties        int                `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
-	MinProperties        int                `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
-	Enum                 []string           `json:"enum,omitempty" yaml:",omitempty"`
-	AllOf                []*ReferenceObject `json:"allOf,omitempty" yaml:"allOf,omitempty"`
-	OneOf                []*ReferenceObject `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
-	AnyOf                []*ReferenceObject `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
-	Not                  *SchemaObject      `json:"not,omitempty" yaml:",omitempty"`
-	AdditionalProperties *SchemaObject      `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
-	Default              interface{}        `json:"default,omitempty" yaml:",omitempty"`
-	Nullable             bool               `json:"nullable,omitempty" yaml:",omitempty"`
-	ReadOnly             bool               `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
-	WriteOnly            bool               `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`
-	Discriminator        *Discriminator     `json:"discriminator,omitempty" yaml:",omitempty"`
+	MultipleOf           interface{}     `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	Minimum              interface{}     `json:"minimum,omitempty" yaml:",omitempty"`
+	Maximum              interface{}     `json:"maximum,omitempty" yaml:",omitempty"`
+	ExclusiveMinimum     interface{}     `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"` // bool in 3.0, number in 3.1
+	ExclusiveMaximum     interface{}     `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"` // bool in 3.0, number in 3.1
+	MaxLength            interface{}     `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinLength            interface{}     `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	Pattern              string          `json:"pattern,omitempty" yaml:",omitempty"`
+	MaxItems             int             `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	MinItems             int             `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	UniqueItems          bool            `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
+	MaxProperties        int             `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
+	MinProperties        int             `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
+	Enum                 []interface{}   `json:"enum,omitempty" yaml:",omitempty"`
+	Const                interface{}     `json:"const,omitempty" yaml:",omitempty"` // OpenAPI 3.1
+	EnumVarNames         []string        `json:"x-enum-varnames,omitempty" yaml:"x-enum-varnames,omitempty"`
+	EnumDescriptions     []string        `json:"x-enum-descriptions,omitempty" yaml:"x-enum-descriptions,omitempty"`
+	Unresolved           string          `json:"x-goas-unresolved,omitempty" yaml:"x-goas-unresolved,omitempty"` // The go type goas could not resolve
+	AllOf                []*SchemaObject `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	OneOf                []*SchemaObject `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	AnyOf                []*SchemaObject `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	Not                  *SchemaObject   `json:"not,omitempty" yaml:",omitempty"`
+	AdditionalProperties *SchemaObject   `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Default              interface{}     `json:"default,omitempty" yaml:",omitempty"`
+	Nullable             bool            `json:"nullable,omitempty" yaml:",omitempty"`
+	ReadOnly             bool            `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	WriteOnly            bool            `json:"writeOnly,omitempty" yaml:"writeOnly,omitempty"`
+	Discriminator        *Discriminator  `json:"discriminator,omitempty" yaml:",omitempty"`
 
 	// Ref is used when SchemaObject is used as a ReferenceObject
 	Ref string `json:"$ref,omitempty" yaml:"$ref,omitempty"`
@@ -291,12 +313,14 @@ type ComponentsObject struct {
 	Schemas         map[string]*SchemaObject         `json:"schemas,omitempty" yaml:",omitempty"`
 	SecuritySchemes map[string]*SecuritySchemeObject `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
 
+	// The following are not generated, they are read from existing documents so that their $refs resolve
+	Responses     map[string]*ResponseObject    `json:"responses,omitempty" yaml:",omitempty"`
+	Parameters    map[string]*ParameterObject   `json:"parameters,omitempty" yaml:",omitempty"`
+	RequestBodies map[string]*RequestBodyObject `json:"requestBodies,omitempty" yaml:"requestBodies,omitempty"`
+	Headers       map[string]*HeaderObject      `json:"headers,omitempty" yaml:",omitempty"`
+
 	// The following are not populated for complexity reasons ...
-	// Responses
-	// Parameters
 	// Examples
-	// RequestBodies
-	// Headers
 	// Links
 	// Callbacks
 }
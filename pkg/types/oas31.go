@@ -0,0 +1,57 @@
+package types
+
+// ConvertToOpenAPI31 rewrites a document built as OpenAPI 3.0 into OpenAPI 3.1, where schemas are JSON Schema 2020-12
+func ConvertToOpenAPI31(o *OpenAPIObject) {
+	o.OpenAPI = OpenAPIVersion31
+	o.WalkSchemas(convertSchemaToOpenAPI31)
+}
+
+func convertSchemaToOpenAPI31(schema *SchemaObject) {
+	convertNullableToOpenAPI31(schema)
+	schema.Minimum, schema.ExclusiveMinimum = convertExclusiveToOpenAPI31(schema.Minimum, schema.ExclusiveMinimum)
+	schema.Maximum, schema.ExclusiveMaximum = convertExclusiveToOpenAPI31(schema.Maximum, schema.ExclusiveMaximum)
+	if schema.Example != nil {
+		schema.Examples = append([]interface{}{schema.Example}, schema.Examples...)
+		schema.Example = nil
+	}
+}
+
+// convertExclusiveToOpenAPI31 replaces the boolean exclusiveMinimum and exclusiveMaximum of 3.0, which modify
+// minimum and maximum, with the numeric exclusive bound of 3.1
+func convertExclusiveToOpenAPI31(bound, exclusive interface{}) (interface{}, interface{}) {
+	isExclusive, ok := exclusive.(bool)
+	if !ok {
+		return bound, exclusive
+	}
+	if isExclusive && bound != nil {
+		return nil, bound
+	}
+	return bound, nil
+}
+
+// convertNullableToOpenAPI31 replaces nullable, which 3.1 removed, with null as one of the types of the schema.
+// Nullable references, which 3.0 can only express as a nullable allOf, become one of the reference or null.
+// Other schemas without a type, such as compositions, become any of the schema or null.
+func convertNullableToOpenAPI31(schema *SchemaObject) {
+	if !schema.Nullable {
+		return
+	}
+	schema.Nullable = false
+	switch {
+	case schema.Type != "":
+		schema.Types = []string{schema.Type, TypeNull}
+		schema.Type = ""
+	case len(schema.AllOf) == 1 && len(schema.OneOf) == 0:
+		schema.OneOf = []*SchemaObject{schema.AllOf[0], {Type: TypeNull}}
+		schema.AllOf = nil
+	default:
+		nonNull := *schema
+		*schema = SchemaObject{
+			ID:                 nonNull.ID,
+			PkgName:            nonNull.PkgName,
+			FieldName:          nonNull.FieldName,
+			DisabledFieldNames: nonNull.DisabledFieldNames,
+			AnyOf:              []*SchemaObject{&nonNull, {Type: TypeNull}},
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToOpenAPI31Nullable(t *testing.T) {
+	tests := map[string]struct {
+		schema     *SchemaObject
+		wantSchema string
+	}{
+		"typed": {
+			schema:     &SchemaObject{Type: TypeString, Nullable: true},
+			wantSchema: `{"type": ["string", "null"]}`,
+		},
+		"reference": {
+			schema: &SchemaObject{
+				AllOf:       []*SchemaObject{{Ref: "#/components/schemas/Pet"}},
+				Description: "Pet",
+				Nullable:    true,
+			},
+			wantSchema: `{"oneOf": [{"$ref": "#/components/schemas/Pet"}, {"type": "null"}], "description": "Pet"}`,
+		},
+		"composition": {
+			schema: &SchemaObject{
+				OneOf:    []*SchemaObject{{Ref: "#/components/schemas/Cat"}, {Ref: "#/components/schemas/Dog"}},
+				Example:  "cat",
+				Nullable: true,
+			},
+			wantSchema: `{
+				"anyOf": [
+					{"oneOf": [{"$ref": "#/components/schemas/Cat"}, {"$ref": "#/components/schemas/Dog"}], "examples": ["cat"]},
+					{"type": "null"}
+				]
+			}`,
+		},
+		"empty": {
+			schema:     &SchemaObject{Nullable: true},
+			wantSchema: `{"anyOf": [{}, {"type": "null"}]}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			openAPI := &OpenAPIObject{Components: ComponentsObject{Schemas: map[string]*SchemaObject{"Pet": tc.schema}}}
+			ConvertToOpenAPI31(openAPI)
+			b, err := json.Marshal(openAPI.Components.Schemas["Pet"])
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.wantSchema, string(b))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Operations of the path item, in the order they are declared
+func (p *PathItemObject) Operations() []*OperationObject {
+	var operations []*OperationObject
+	for _, operation := range []*OperationObject{p.Get, p.Post, p.Patch, p.Put, p.Delete, p.Options, p.Head, p.Trace} {
+		if operation != nil {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+// SetOperation of the path item for an http method, false is returned for methods a path item has no operation for
+func (p *PathItemObject) SetOperation(method string, operation *OperationObject) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		p.Get = operation
+	case http.MethodPost:
+		p.Post = operation
+	case http.MethodPatch:
+		p.Patch = operation
+	case http.MethodPut:
+		p.Put = operation
+	case http.MethodDelete:
+		p.Delete = operation
+	case http.MethodOptions:
+		p.Options = operation
+	case http.MethodHead:
+		p.Head = operation
+	case http.MethodTrace:
+		p.Trace = operation
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"go/token"
+	"path/filepath"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFLog is a static analysis results interchange format log, as read by code scanning tools
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     SARIFMessage       `json:"shortDescription"`
+	DefaultConfiguration SARIFConfiguration `json:"defaultConfiguration"`
+}
+
+type SARIFConfiguration struct {
+	Level string `json:"level"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation *SARIFPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type SARIFLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// LintSARIF renders lint results as a SARIF log. locate returns the source position of a json pointer, results are
+// located by their pointer only if it has none.
+func LintSARIF(results []LintResult, locate func(pointer string) token.Position) SARIFLog {
+	driver := SARIFDriver{Name: "goas", InformationURI: "https://github.com/deanstalker/goas"}
+	for _, rule := range LintRules {
+		driver.Rules = append(driver.Rules, SARIFRule{
+			ID:                   rule.ID,
+			ShortDescription:     SARIFMessage{Text: rule.Description},
+			DefaultConfiguration: SARIFConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	run := SARIFRun{Tool: SARIFTool{Driver: driver}, Results: []SARIFResult{}}
+	for _, result := range results {
+		location := SARIFLocation{
+			LogicalLocations: []SARIFLogicalLocation{{FullyQualifiedName: result.Pointer, Kind: "object"}},
+		}
+		if position := locate(result.Pointer); position.Filename != "" {
+			location.PhysicalLocation = &SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: filepath.ToSlash(position.Filename)},
+			}
+			if position.Line > 0 {
+				location.PhysicalLocation.Region = &SARIFRegion{StartLine: position.Line, StartColumn: position.Column}
+			}
+		}
+		run.Results = append(run.Results, SARIFResult{
+			RuleID:    result.Rule,
+			Level:     sarifLevel(result.Severity),
+			Message:   SARIFMessage{Text: result.Message},
+			Locations: []SARIFLocation{location},
+		})
+	}
+	return SARIFLog{Version: sarifVersion, Schema: sarifSchema, Runs: []SARIFRun{run}}
+}
+
+// sarifLevel of a severity, SARIF has notes rather than info
+func sarifLevel(severity string) string {
+	if severity == SeverityInfo {
+		return "note"
+	}
+	return severity
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gopkg.in/yaml.v2"
+)
+
+// schemaObject has the fields of SchemaObject without its marshalling methods
+type schemaObject SchemaObject
+
+// MarshalJSON marshals Types as the type of the schema, if set
+func (s SchemaObject) MarshalJSON() ([]byte, error) {
+	if len(s.Types) == 0 {
+		return json.Marshal(schemaObject(s))
+	}
+	return json.Marshal(struct {
+		Type []string `json:"type"`
+		schemaObject
+	}{
+		Type:         s.Types,
+		schemaObject: schemaObject(s),
+	})
+}
+
+// UnmarshalJSON reads a type array into Types, and a single type into Type
+func (s *SchemaObject) UnmarshalJSON(b []byte) error {
+	in := struct {
+		Type json.RawMessage `json:"type"`
+		*schemaObject
+	}{
+		schemaObject: (*schemaObject)(s),
+	}
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	if len(in.Type) == 0 {
+		return nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(in.Type), []byte("[")) {
+		return json.Unmarshal(in.Type, &s.Types)
+	}
+	return json.Unmarshal(in.Type, &s.Type)
+}
+
+// MarshalYAML marshals Types as the type of the schema, if set. yaml cannot inline a field of the same name,
+// so the schema is marshalled in the order of its json encoding instead.
+func (s SchemaObject) MarshalYAML() (interface{}, error) {
+	if len(s.Types) == 0 {
+		return schemaObject(s), nil
+	}
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(data)
+}
+
+// UnmarshalYAML reads a schema the same way UnmarshalJSON does
+func (s *SchemaObject) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var in yaml.MapSlice
+	if err := unmarshal(&in); err != nil {
+		return err
+	}
+	data, err := Document(in).MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalJSON(data)
+}
+
+// Copy the schema and the schemas nested in it
+func (s *SchemaObject) Copy() *SchemaObject {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Items = s.Items.Copy()
+	c.Not = s.Not.Copy()
+	c.AdditionalProperties = s.AdditionalProperties.Copy()
+	if s.Properties != nil {
+		c.Properties = NewOrderedMap()
+		for _, key := range s.Properties.Keys() {
+			property, _ := s.Properties.Get(key)
+			if propertySchema, ok := property.(*SchemaObject); ok {
+				property = propertySchema.Copy()
+			}
+			c.Properties.Set(key, property)
+		}
+	}
+	c.AllOf = copySchemas(s.AllOf)
+	c.OneOf = copySchemas(s.OneOf)
+	c.AnyOf = copySchemas(s.AnyOf)
+	return &c
+}
+
+func copySchemas(schemas []*SchemaObject) []*SchemaObject {
+	if schemas == nil {
+		return nil
+	}
+	copies := make([]*SchemaObject, len(schemas))
+	for i := range schemas {
+		copies[i] = schemas[i].Copy()
+	}
+	return copies
+}
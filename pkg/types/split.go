@@ -0,0 +1,275 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// layout is where the sections of a document are split to, and how their entries are referenced within the document
+type layout struct {
+	root       string
+	schemas    []string // keys of the schemas in the document
+	schemasDir string
+	schemaRef  string // prefix of the local references to schemas
+}
+
+func documentLayout(document yaml.MapSlice) layout {
+	if _, ok := getKey(document, "swagger"); ok {
+		return layout{root: "swagger", schemas: []string{"definitions"}, schemasDir: "definitions", schemaRef: "#/definitions/"}
+	}
+	return layout{root: "openapi", schemas: []string{"components", "schemas"}, schemasDir: "components/schemas", schemaRef: "#/components/schemas/"}
+}
+
+// pathSections are split into a file per entry, each entry being a path item
+var pathSections = []string{"paths", "webhooks"}
+
+// SplitDocument splits a document into a root file, a file per path item under paths/ and webhooks/, and a file per
+// schema under components/schemas/, or definitions/ for Swagger. Entries are replaced by relative $refs to their file,
+// and the local $refs of the split files by relative $refs to the schema files. Files are keyed by their path, relative
+// to the root file, and use the ext extension.
+func SplitDocument(document interface{}, ext string) (map[string]Document, error) {
+	d, err := NewDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	root := yaml.MapSlice(d)
+
+	l := documentLayout(root)
+	files := map[string]Document{}
+	schemaFiles := map[string]string{}
+	split := func(entries yaml.MapSlice, dir string, fileName func(key string) string) {
+		taken := map[string]bool{}
+		for i, entry := range entries {
+			name := fileName(fmt.Sprint(entry.Key))
+			for n := 2; taken[name]; n++ {
+				name = fmt.Sprintf("%s_%d", fileName(fmt.Sprint(entry.Key)), n)
+			}
+			taken[name] = true
+
+			file := path.Join(dir, name+"."+ext)
+			if dir == l.schemasDir {
+				schemaFiles[fmt.Sprint(entry.Key)] = file
+			}
+			if value, ok := entry.Value.(yaml.MapSlice); ok {
+				files[file] = Document(value)
+			}
+			entries[i].Value = yaml.MapSlice{{Key: "$ref", Value: file}}
+		}
+	}
+
+	if schemas, ok := getPath(root, l.schemas); ok {
+		split(schemas, l.schemasDir, func(key string) string { return key })
+	}
+	for _, section := range pathSections {
+		if paths, ok := getPath(root, []string{section}); ok {
+			if section == "paths" {
+				split(paths, section, pathFileName)
+			} else {
+				split(paths, section, func(key string) string { return key })
+			}
+		}
+	}
+
+	for file, content := range files {
+		dir := path.Dir(file)
+		rewriteRefs(yaml.MapSlice(content), func(ref string) string {
+			schemaFile, ok := schemaFiles[strings.TrimPrefix(ref, l.schemaRef)]
+			if !strings.HasPrefix(ref, l.schemaRef) || !ok {
+				return ref
+			}
+			rel, err := filepath.Rel(dir, schemaFile)
+			if err != nil {
+				return ref
+			}
+			return filepath.ToSlash(rel)
+		})
+	}
+	files[l.root+"."+ext] = Document(root)
+	return files, nil
+}
+
+// pathFileName names the file of a path item after its path, with / replaced by @
+func pathFileName(p string) string {
+	name := strings.ReplaceAll(strings.Trim(p, "/"), "/", "@")
+	if name == "" {
+		return "root"
+	}
+	return name
+}
+
+// BundleDocument reads a document split into multiple files back into one, from its root file. Path items referenced
+// by a file are inlined, and schemas referenced by a file are added to the schemas of the document, named after their
+// file, and referenced locally.
+func BundleDocument(rootFile string) (Document, error) {
+	root, err := readDocumentFile(rootFile)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bundler{
+		layout:      documentLayout(root),
+		schemaNames: map[string]string{},
+		taken:       map[string]bool{},
+	}
+	dir := filepath.Dir(rootFile)
+
+	// schemas of the root file keep their name
+	schemas, _ := getPath(root, b.layout.schemas)
+	rootSchemaFiles := map[string]string{}
+	for _, entry := range schemas {
+		b.taken[fmt.Sprint(entry.Key)] = true
+	}
+	for _, entry := range schemas {
+		if file, ok := externalRef(entry.Value); ok {
+			schemaFile := filepath.Join(dir, filepath.FromSlash(file))
+			rootSchemaFiles[fmt.Sprint(entry.Key)] = schemaFile
+			b.addSchemaFile(schemaFile, fmt.Sprint(entry.Key))
+		}
+	}
+
+	for _, section := range pathSections {
+		paths, _ := getPath(root, []string{section})
+		for i, entry := range paths {
+			file, ok := externalRef(entry.Value)
+			if !ok {
+				continue
+			}
+			pathItemFile := filepath.Join(dir, filepath.FromSlash(file))
+			pathItem, err := readDocumentFile(pathItemFile)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.resolveRefs(pathItem, pathItemFile); err != nil {
+				return nil, err
+			}
+			paths[i].Value = pathItem
+		}
+	}
+	if err := b.resolveRefs(root, rootFile); err != nil {
+		return nil, err
+	}
+
+	// schema files may reference further schema files, which are added to the order as they are found
+	loaded := map[string]yaml.MapSlice{}
+	for i := 0; i < len(b.order); i++ {
+		schemaFile := b.order[i]
+		schema, err := readDocumentFile(schemaFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.resolveRefs(schema, schemaFile); err != nil {
+			return nil, err
+		}
+		loaded[schemaFile] = schema
+	}
+
+	bundled := yaml.MapSlice{}
+	for _, entry := range schemas {
+		if schemaFile, ok := rootSchemaFiles[fmt.Sprint(entry.Key)]; ok {
+			entry.Value = loaded[schemaFile]
+		}
+		bundled = append(bundled, entry)
+	}
+	for _, schemaFile := range b.order {
+		if name := b.schemaNames[schemaFile]; !hasKey(bundled, name) {
+			bundled = append(bundled, yaml.MapItem{Key: name, Value: loaded[schemaFile]})
+		}
+	}
+	if len(bundled) > 0 {
+		root = setPath(root, b.layout.schemas, bundled)
+	}
+	return Document(root), nil
+}
+
+type bundler struct {
+	layout layout
+
+	// schemaNames of the schema files, keyed by their path, and the order they were found in
+	schemaNames map[string]string
+	order       []string
+	taken       map[string]bool
+}
+
+func (b *bundler) addSchemaFile(file, name string) {
+	if _, ok := b.schemaNames[file]; ok {
+		return
+	}
+	b.schemaNames[file] = name
+	b.order = append(b.order, file)
+}
+
+// resolveRefs replaces the external $refs of a value read from file by local $refs to the schemas they are bundled as
+func (b *bundler) resolveRefs(value interface{}, file string) error {
+	var err error
+	rewriteRefs(value, func(ref string) string {
+		if strings.HasPrefix(ref, "#") || err != nil {
+			return ref
+		}
+		if strings.Contains(ref, "#") {
+			err = fmt.Errorf("%s: $ref %s to a part of a file is not supported", file, ref)
+			return ref
+		}
+		schemaFile := filepath.Join(filepath.Dir(file), filepath.FromSlash(ref))
+		name, ok := b.schemaNames[schemaFile]
+		if !ok {
+			base := strings.TrimSuffix(filepath.Base(schemaFile), filepath.Ext(schemaFile))
+			name = base
+			for n := 2; b.taken[name]; n++ {
+				name = fmt.Sprintf("%s_%d", base, n)
+			}
+			b.taken[name] = true
+			b.addSchemaFile(schemaFile, name)
+		}
+		return b.layout.schemaRef + name
+	})
+	return err
+}
+
+func readDocumentFile(file string) (yaml.MapSlice, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return document, nil
+}
+
+// externalRef returns the file of a value that is only a $ref to another file
+func externalRef(value interface{}) (string, bool) {
+	object := asMapSlice(value)
+	if len(object) != 1 {
+		return "", false
+	}
+	ref, ok := getKey(object, "$ref")
+	if !ok {
+		return "", false
+	}
+	file, ok := ref.(string)
+	return file, ok && !strings.HasPrefix(file, "#")
+}
+
+// rewriteRefs replaces every $ref nested in value by the result of fn
+func rewriteRefs(value interface{}, fn func(ref string) string) {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		for i, item := range v {
+			if ref, ok := item.Value.(string); ok && item.Key == "$ref" {
+				v[i].Value = fn(ref)
+				continue
+			}
+			rewriteRefs(item.Value, fn)
+		}
+	case []interface{}:
+		for _, item := range v {
+			rewriteRefs(item, fn)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package types
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSplitDocument(t *testing.T) {
+	openAPI := &OpenAPIObject{
+		OpenAPI: OpenAPIVersion,
+		Info:    InfoObject{Title: "Pets", Version: "1.0.0"},
+		Paths: PathsObject{
+			"/": {Get: &OperationObject{Responses: ResponsesObject{"204": {Description: "Ok"}}}},
+			"/pets/{id}": {
+				Get: &OperationObject{
+					OperationID: "getPet",
+					Responses: ResponsesObject{
+						"200": {
+							Description: "A pet",
+							Content:     map[string]*MediaTypeObject{ContentTypeJSON: {Schema: SchemaObject{Ref: "#/components/schemas/Pet"}}},
+						},
+					},
+				},
+			},
+		},
+		Webhooks: PathsObject{
+			"newPet": {Post: &OperationObject{
+				RequestBody: &RequestBodyObject{
+					Content: map[string]*MediaTypeObject{ContentTypeJSON: {Schema: SchemaObject{Ref: "#/components/schemas/Pet"}}},
+				},
+				Responses: ResponsesObject{"200": {Description: "Ok"}},
+			}},
+		},
+		Components: ComponentsObject{
+			Schemas: map[string]*SchemaObject{
+				"Owner": {Type: TypeObject, Properties: NewOrderedMap().Set("name", &SchemaObject{Type: TypeString})},
+				"Pet": {
+					Type: TypeObject,
+					Properties: NewOrderedMap().
+						Set("id", &SchemaObject{Type: TypeInteger, Format: "int64"}).
+						Set("owner", &SchemaObject{Ref: "#/components/schemas/Owner"}),
+				},
+			},
+		},
+	}
+
+	files, err := SplitDocument(openAPI, "yaml")
+	assert.NoError(t, err)
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{
+		"components/schemas/Owner.yaml",
+		"components/schemas/Pet.yaml",
+		"openapi.yaml",
+		"paths/pets@{id}.yaml",
+		"paths/root.yaml",
+		"webhooks/newPet.yaml",
+	}, names)
+
+	assertJSON := func(expected string, document Document) {
+		b, err := json.Marshal(document)
+		assert.NoError(t, err)
+		assert.JSONEq(t, expected, string(b))
+	}
+	assertJSON(`{
+		"openapi": "3.0.0",
+		"info": {"title": "Pets", "version": "1.0.0"},
+		"paths": {"/": {"$ref": "paths/root.yaml"}, "/pets/{id}": {"$ref": "paths/pets@{id}.yaml"}},
+		"components": {"schemas": {
+			"Owner": {"$ref": "components/schemas/Owner.yaml"},
+			"Pet": {"$ref": "components/schemas/Pet.yaml"}
+		}},
+		"webhooks": {"newPet": {"$ref": "webhooks/newPet.yaml"}}
+	}`, files["openapi.yaml"])
+	assertJSON(`{"get": {
+		"operationId": "getPet",
+		"responses": {"200": {"description": "A pet", "content": {"application/json": {"schema": {"$ref": "../components/schemas/Pet.yaml"}}}}}
+	}}`, files["paths/pets@{id}.yaml"])
+	assertJSON(`{
+		"type": "object",
+		"properties": {"id": {"type": "integer", "format": "int64"}, "owner": {"$ref": "Owner.yaml"}}
+	}`, files["components/schemas/Pet.yaml"])
+
+	// bundling the files back gives the document that was split
+	dir := t.TempDir()
+	for name, document := range files {
+		data, err := yaml.Marshal(document)
+		assert.NoError(t, err)
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, data, 0o644))
+	}
+	bundled, err := BundleDocument(filepath.Join(dir, "openapi.yaml"))
+	assert.NoError(t, err)
+	expected, err := json.Marshal(openAPI)
+	assert.NoError(t, err)
+	assertJSON(string(expected), bundled)
+}
+
+func TestBundleDocument(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"openapi.yaml": `
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+components:
+  schemas:
+    Error: {type: string}
+`,
+		"paths/pets.yaml": `
+get:
+  responses:
+    "200":
+      description: Pets
+      content:
+        application/json:
+          schema: {type: array, items: {$ref: ../schemas/Pet.yaml}}
+    default:
+      description: Error
+      content:
+        application/json:
+          schema: {$ref: "#/components/schemas/Error"}
+`,
+		"schemas/Pet.yaml": `
+type: object
+properties:
+  error: {$ref: errors/Error.yaml}
+`,
+		"schemas/errors/Error.yaml": `type: object`,
+		"broken.yaml": `
+openapi: 3.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        default:
+          description: Error
+          content:
+            application/json:
+              schema: {$ref: "schemas/Pet.yaml#/properties/error"}
+`,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	bundled, err := BundleDocument(filepath.Join(dir, "openapi.yaml"))
+	assert.NoError(t, err)
+	b, err := json.Marshal(bundled)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"openapi": "3.0.0",
+		"info": {"title": "Pets", "version": "1.0.0"},
+		"paths": {"/pets": {"get": {"responses": {
+			"200": {"description": "Pets", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}},
+			"default": {"description": "Error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+		}}}},
+		"components": {"schemas": {
+			"Error": {"type": "string"},
+			"Pet": {"type": "object", "properties": {"error": {"$ref": "#/components/schemas/Error_2"}}},
+			"Error_2": {"type": "object"}
+		}}
+	}`, string(b))
+
+	_, err = BundleDocument(filepath.Join(dir, "broken.yaml"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "to a part of a file is not supported")
+	}
+}
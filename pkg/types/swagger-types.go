@@ -0,0 +1,114 @@
+package types
+
+const (
+	SwaggerVersion = "2.0"
+
+	SwaggerInFormData = "formData"
+	SwaggerTypeFile   = "file"
+)
+
+// SwaggerObject is the root of a Swagger 2.0 document, schemas are shared with OpenAPI as their keywords are a subset
+type SwaggerObject struct {
+	Swagger  string     `json:"swagger" yaml:"swagger"` // Required
+	Info     InfoObject `json:"info" yaml:"info"`       // Required
+	Host     string     `json:"host,omitempty" yaml:",omitempty"`
+	BasePath string     `json:"basePath,omitempty" yaml:"basePath,omitempty"`
+	Schemes  []string   `json:"schemes,omitempty" yaml:",omitempty"`
+	Consumes []string   `json:"consumes,omitempty" yaml:",omitempty"`
+	Produces []string   `json:"produces,omitempty" yaml:",omitempty"`
+
+	Paths map[string]*SwaggerPathItemObject `json:"paths" yaml:"paths"` // Required
+
+	Definitions         map[string]*SchemaObject                `json:"definitions,omitempty" yaml:",omitempty"`
+	SecurityDefinitions map[string]*SwaggerSecuritySchemeObject `json:"securityDefinitions,omitempty" yaml:"securityDefinitions,omitempty"`
+	Security            []map[string][]string                   `json:"security,omitempty" yaml:",omitempty"`
+
+	Tags         []TagObject                  `json:"tags,omitempty" yaml:",omitempty"`
+	ExternalDocs *ExternalDocumentationObject `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+}
+
+type SwaggerPathItemObject struct {
+	Get     *SwaggerOperationObject `json:"get,omitempty" yaml:",omitempty"`
+	Put     *SwaggerOperationObject `json:"put,omitempty" yaml:",omitempty"`
+	Post    *SwaggerOperationObject `json:"post,omitempty" yaml:",omitempty"`
+	Delete  *SwaggerOperationObject `json:"delete,omitempty" yaml:",omitempty"`
+	Options *SwaggerOperationObject `json:"options,omitempty" yaml:",omitempty"`
+	Head    *SwaggerOperationObject `json:"head,omitempty" yaml:",omitempty"`
+	Patch   *SwaggerOperationObject `json:"patch,omitempty" yaml:",omitempty"`
+}
+
+type SwaggerOperationObject struct {
+	Tags         []string                     `json:"tags,omitempty" yaml:",omitempty"`
+	Summary      string                       `json:"summary,omitempty" yaml:",omitempty"`
+	Description  string                       `json:"description,omitempty" yaml:",omitempty"`
+	ExternalDocs *ExternalDocumentationObject `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+	OperationID  string                       `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Consumes     []string                     `json:"consumes,omitempty" yaml:",omitempty"`
+	Produces     []string                     `json:"produces,omitempty" yaml:",omitempty"`
+	Parameters   []*SwaggerParameterObject    `json:"parameters,omitempty" yaml:",omitempty"`
+
+	Responses map[string]*SwaggerResponseObject `json:"responses" yaml:"responses"` // Required
+
+	Deprecated bool                  `json:"deprecated,omitempty" yaml:",omitempty"`
+	Security   []map[string][]string `json:"security,omitempty" yaml:",omitempty"`
+}
+
+// SwaggerSimpleSchema is the subset of a schema that parameters other than body parameters, and headers, may have
+type SwaggerSimpleSchema struct {
+	Type             string               `json:"type,omitempty" yaml:",omitempty"`
+	Format           string               `json:"format,omitempty" yaml:",omitempty"`
+	Items            *SwaggerSimpleSchema `json:"items,omitempty" yaml:",omitempty"`
+	CollectionFormat string               `json:"collectionFormat,omitempty" yaml:"collectionFormat,omitempty"`
+	Default          interface{}          `json:"default,omitempty" yaml:",omitempty"`
+	Maximum          interface{}          `json:"maximum,omitempty" yaml:",omitempty"`
+	ExclusiveMaximum interface{}          `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	Minimum          interface{}          `json:"minimum,omitempty" yaml:",omitempty"`
+	ExclusiveMinimum interface{}          `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	MaxLength        interface{}          `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinLength        interface{}          `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	Pattern          string               `json:"pattern,omitempty" yaml:",omitempty"`
+	MaxItems         int                  `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	MinItems         int                  `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	UniqueItems      bool                 `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
+	Enum             []interface{}        `json:"enum,omitempty" yaml:",omitempty"`
+	MultipleOf       interface{}          `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+}
+
+type SwaggerParameterObject struct {
+	Name        string `json:"name" yaml:"name"` // Required
+	In          string `json:"in" yaml:"in"`     // Required. Possible values are "query", "header", "path", "formData" or "body"
+	Description string `json:"description,omitempty" yaml:",omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:",omitempty"`
+
+	// Schema is only used by body parameters, the others are described by SwaggerSimpleSchema
+	Schema *SchemaObject `json:"schema,omitempty" yaml:",omitempty"`
+
+	SwaggerSimpleSchema `yaml:",inline"`
+}
+
+type SwaggerResponseObject struct {
+	Description string                          `json:"description" yaml:"description"` // Required
+	Schema      *SchemaObject                   `json:"schema,omitempty" yaml:",omitempty"`
+	Headers     map[string]*SwaggerHeaderObject `json:"headers,omitempty" yaml:",omitempty"`
+}
+
+type SwaggerHeaderObject struct {
+	Description string `json:"description,omitempty" yaml:",omitempty"`
+
+	SwaggerSimpleSchema `yaml:",inline"`
+}
+
+type SwaggerSecuritySchemeObject struct {
+	Type        string `json:"type" yaml:"type"` // Required. Possible values are "basic", "apiKey" or "oauth2"
+	Description string `json:"description,omitempty" yaml:",omitempty"`
+
+	// apiKey
+	Name string `json:"name,omitempty" yaml:",omitempty"`
+	In   string `json:"in,omitempty" yaml:",omitempty"`
+
+	// oauth2
+	Flow             string            `json:"flow,omitempty" yaml:",omitempty"`
+	AuthorizationURL string            `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty" yaml:",omitempty"`
+}
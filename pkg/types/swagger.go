@@ -0,0 +1,416 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	refPrefixSchemas     = "#/components/schemas/"
+	refPrefixDefinitions = "#/definitions/"
+
+	contentTypeFormURLEncoded = "application/x-www-form-urlencoded"
+)
+
+// ConversionWarning is a construct that cannot be expressed by the version a document is converted to, found at Location
+type ConversionWarning struct {
+	Location  string
+	Construct string
+}
+
+// ConvertToSwagger down-converts an OpenAPI 3.0 document to Swagger 2.0. Constructs Swagger cannot express are dropped,
+// and returned as warnings. The OpenAPI document is left as is.
+func ConvertToSwagger(o *OpenAPIObject) (*SwaggerObject, []ConversionWarning) {
+	swagger := &SwaggerObject{
+		Swagger:             SwaggerVersion,
+		Info:                o.Info,
+		Paths:               map[string]*SwaggerPathItemObject{},
+		Definitions:         map[string]*SchemaObject{},
+		SecurityDefinitions: map[string]*SwaggerSecuritySchemeObject{},
+		Tags:                o.Tags,
+		ExternalDocs:        o.ExternalDocs,
+	}
+	swagger.Info.Summary = ""
+	if o.Info.License != nil {
+		license := *o.Info.License
+		license.Identifier = ""
+		swagger.Info.License = &license
+	}
+
+	// security schemes are converted first, so that the requirements of the schemes that are dropped are dropped too.
+	// Their warnings are reported last, in the order of the document.
+	schemes := &swaggerConverter{openAPI: o}
+	for _, name := range sortedKeys(o.Components.SecuritySchemes) {
+		if scheme := schemes.convertSecurityScheme("securityDefinitions."+name, o.Components.SecuritySchemes[name]); scheme != nil {
+			swagger.SecurityDefinitions[name] = scheme
+		}
+	}
+	c := &swaggerConverter{openAPI: o, securityDefinitions: swagger.SecurityDefinitions}
+	swagger.Security = c.convertSecurity("security", o.Security)
+
+	c.convertServers(swagger)
+	for _, path := range sortedKeys(o.Paths) {
+		swagger.Paths[path] = c.convertPathItem("paths."+path, o.Paths[path])
+	}
+	for _, name := range sortedKeys(o.Webhooks) {
+		c.warn("webhooks."+name, "webhooks")
+	}
+	for _, name := range sortedKeys(o.Components.Schemas) {
+		swagger.Definitions[name] = c.convertSchema("definitions."+name, o.Components.Schemas[name])
+	}
+	c.warnings = append(c.warnings, schemes.warnings...)
+
+	return swagger, c.warnings
+}
+
+type swaggerConverter struct {
+	openAPI             *OpenAPIObject
+	securityDefinitions map[string]*SwaggerSecuritySchemeObject
+	warnings            []ConversionWarning
+}
+
+func (c *swaggerConverter) warn(location, construct string) {
+	c.warnings = append(c.warnings, ConversionWarning{Location: location, Construct: construct})
+}
+
+// convertServers describes the first server by its host, base path and scheme, as Swagger has a single server
+func (c *swaggerConverter) convertServers(swagger *SwaggerObject) {
+	if len(c.openAPI.Servers) == 0 {
+		return
+	}
+	if len(c.openAPI.Servers) > 1 {
+		c.warn("servers", "multiple servers")
+	}
+	server := c.openAPI.Servers[0]
+	serverURL := server.URL
+	for name, variable := range server.Variables {
+		serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", variable.Default)
+	}
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		c.warn("servers[0]", fmt.Sprintf("server url %s", server.URL))
+		return
+	}
+	swagger.Host = parsed.Host
+	swagger.BasePath = parsed.Path
+	if parsed.Scheme != "" {
+		swagger.Schemes = []string{parsed.Scheme}
+	}
+}
+
+func (c *swaggerConverter) convertPathItem(location string, pathItem *PathItemObject) *SwaggerPathItemObject {
+	if pathItem.Trace != nil {
+		c.warn(location+".trace", "trace operations")
+	}
+	return &SwaggerPathItemObject{
+		Get:     c.convertOperation(location+".get", pathItem.Get),
+		Put:     c.convertOperation(location+".put", pathItem.Put),
+		Post:    c.convertOperation(location+".post", pathItem.Post),
+		Delete:  c.convertOperation(location+".delete", pathItem.Delete),
+		Options: c.convertOperation(location+".options", pathItem.Options),
+		Head:    c.convertOperation(location+".head", pathItem.Head),
+		Patch:   c.convertOperation(location+".patch", pathItem.Patch),
+	}
+}
+
+func (c *swaggerConverter) convertOperation(location string, operation *OperationObject) *SwaggerOperationObject {
+	if operation == nil {
+		return nil
+	}
+	swaggerOperation := &SwaggerOperationObject{
+		Tags:         operation.Tags,
+		Summary:      operation.Summary,
+		Description:  operation.Description,
+		ExternalDocs: operation.ExternalDocs,
+		OperationID:  operation.OperationID,
+		Responses:    map[string]*SwaggerResponseObject{},
+		Deprecated:   operation.Deprecated,
+		Security:     c.convertSecurity(location+".security", operation.Security),
+	}
+	if len(operation.Servers) > 0 {
+		c.warn(location+".servers", "operation servers")
+	}
+
+	for i := range operation.Parameters {
+		if parameter := c.convertParameter(fmt.Sprintf("%s.parameters[%d]", location, i), &operation.Parameters[i]); parameter != nil {
+			swaggerOperation.Parameters = append(swaggerOperation.Parameters, parameter)
+		}
+	}
+	if operation.RequestBody != nil {
+		swaggerOperation.Consumes = sortedKeys(operation.RequestBody.Content)
+		swaggerOperation.Parameters = append(swaggerOperation.Parameters, c.convertRequestBody(location+".requestBody", operation.RequestBody)...)
+	}
+
+	produces := map[string]bool{}
+	for _, status := range sortedKeys(operation.Responses) {
+		response := operation.Responses[status]
+		for contentType := range response.Content {
+			produces[contentType] = true
+		}
+		swaggerOperation.Responses[status] = c.convertResponse(location+".responses."+status, response)
+	}
+	swaggerOperation.Produces = sortedKeys(produces)
+
+	return swaggerOperation
+}
+
+func (c *swaggerConverter) convertParameter(location string, parameter *ParameterObject) *SwaggerParameterObject {
+	if parameter.In == "cookie" {
+		c.warn(location, "cookie parameters")
+		return nil
+	}
+	return &SwaggerParameterObject{
+		Name:                parameter.Name,
+		In:                  parameter.In,
+		Description:         parameter.Description,
+		Required:            parameter.Required,
+		SwaggerSimpleSchema: c.convertSimpleSchema(location, parameter.Schema),
+	}
+}
+
+// convertRequestBody describes forms as formData parameters, and any other content as a body parameter
+func (c *swaggerConverter) convertRequestBody(location string, requestBody *RequestBodyObject) []*SwaggerParameterObject {
+	contentTypes := sortedKeys(requestBody.Content)
+	if len(contentTypes) == 0 {
+		return nil
+	}
+	if len(contentTypes) > 1 {
+		c.warn(location+".content", "request bodies of different schemas per content type")
+	}
+
+	contentType := contentTypes[0]
+	schema := &requestBody.Content[contentType].Schema
+	if contentType != ContentTypeForm && contentType != contentTypeFormURLEncoded {
+		return []*SwaggerParameterObject{{
+			Name:        InBody,
+			In:          InBody,
+			Description: requestBody.Description,
+			Required:    requestBody.Required,
+			Schema:      c.convertSchema(location+".content."+contentType+".schema", schema),
+		}}
+	}
+
+	var parameters []*SwaggerParameterObject
+	if schema.Properties == nil {
+		return parameters
+	}
+	for _, name := range schema.Properties.Keys() {
+		property, _ := schema.Properties.Get(name)
+		propertySchema, ok := property.(*SchemaObject)
+		if !ok {
+			continue
+		}
+		propertyLocation := location + ".content." + contentType + ".schema.properties." + name
+		parameter := &SwaggerParameterObject{
+			Name:        name,
+			In:          SwaggerInFormData,
+			Description: propertySchema.Description,
+			Required:    containsString(schema.Required, name),
+		}
+		switch {
+		case propertySchema.Format == "binary":
+			parameter.Type = SwaggerTypeFile
+		case propertySchema.Items != nil && propertySchema.Items.Format == "binary":
+			c.warn(propertyLocation, "multiple file uploads")
+			parameter.Type = SwaggerTypeFile
+		default:
+			parameter.SwaggerSimpleSchema = c.convertSimpleSchema(propertyLocation, propertySchema)
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+// convertResponse describes the response by the schema of its json content, or of its first content type otherwise
+func (c *swaggerConverter) convertResponse(location string, response *ResponseObject) *SwaggerResponseObject {
+	swaggerResponse := &SwaggerResponseObject{
+		Description: response.Description,
+	}
+	contentTypes := sortedKeys(response.Content)
+	if _, ok := response.Content[ContentTypeJSON]; ok {
+		contentTypes = append([]string{ContentTypeJSON}, contentTypes...)
+	}
+	if len(contentTypes) > 0 {
+		swaggerResponse.Schema = c.convertSchema(location+".content."+contentTypes[0]+".schema", &response.Content[contentTypes[0]].Schema)
+	}
+	for _, name := range sortedKeys(response.Headers) {
+		if swaggerResponse.Headers == nil {
+			swaggerResponse.Headers = map[string]*SwaggerHeaderObject{}
+		}
+		header := response.Headers[name]
+		swaggerResponse.Headers[name] = &SwaggerHeaderObject{
+			Description:         header.Description,
+			SwaggerSimpleSchema: c.convertSimpleSchema(location+".headers."+name, header.Schema),
+		}
+	}
+	return swaggerResponse
+}
+
+// convertSchema copies a schema with its references pointing to definitions, and without the keywords Swagger lacks
+func (c *swaggerConverter) convertSchema(location string, schema *SchemaObject) *SchemaObject {
+	if schema == nil {
+		return nil
+	}
+	converted := schema.Copy()
+	walkSchema(converted, map[*SchemaObject]bool{}, func(s *SchemaObject) {
+		s.Ref = strings.Replace(s.Ref, refPrefixSchemas, refPrefixDefinitions, 1)
+		if len(s.OneOf) > 0 {
+			c.warn(location, "oneOf")
+		}
+		if len(s.AnyOf) > 0 {
+			c.warn(location, "anyOf")
+		}
+		if s.Not != nil {
+			c.warn(location, "not")
+		}
+		s.OneOf, s.AnyOf, s.Not, s.Discriminator = nil, nil, nil, nil
+		s.Nullable, s.WriteOnly, s.Deprecated = false, false, false
+	})
+	return converted
+}
+
+// convertSimpleSchema describes a parameter or header by its schema, following references to component schemas,
+// as only body parameters may reference a definition
+func (c *swaggerConverter) convertSimpleSchema(location string, schema *SchemaObject) SwaggerSimpleSchema {
+	schema = c.resolve(schema)
+	if schema == nil {
+		return SwaggerSimpleSchema{Type: TypeString}
+	}
+	if schema.Type == TypeObject || schema.Type == "" {
+		c.warn(location, "parameters and headers of object schemas")
+		return SwaggerSimpleSchema{Type: TypeString}
+	}
+	simple := SwaggerSimpleSchema{
+		Type:             schema.Type,
+		Format:           schema.Format,
+		Default:          schema.Default,
+		Maximum:          schema.Maximum,
+		ExclusiveMaximum: schema.ExclusiveMaximum,
+		Minimum:          schema.Minimum,
+		ExclusiveMinimum: schema.ExclusiveMinimum,
+		MaxLength:        schema.MaxLength,
+		MinLength:        schema.MinLength,
+		Pattern:          schema.Pattern,
+		MaxItems:         schema.MaxItems,
+		MinItems:         schema.MinItems,
+		UniqueItems:      schema.UniqueItems,
+		Enum:             schema.Enum,
+		MultipleOf:       schema.MultipleOf,
+	}
+	if schema.Type == TypeArray {
+		items := c.convertSimpleSchema(location+".items", schema.Items)
+		simple.Items = &items
+		simple.CollectionFormat = "csv"
+	}
+	return simple
+}
+
+// resolve follows the references of a schema to the component schema they reference
+func (c *swaggerConverter) resolve(schema *SchemaObject) *SchemaObject {
+	for seen := 0; schema != nil && schema.Ref != "" && seen <= len(c.openAPI.Components.Schemas); seen++ {
+		schema = c.openAPI.Components.Schemas[strings.TrimPrefix(schema.Ref, refPrefixSchemas)]
+	}
+	return schema
+}
+
+// convertSecurity drops the schemes Swagger cannot express from security requirements, and the requirements that are
+// left without a scheme. Requirements without schemes to begin with, which make security optional, are kept.
+func (c *swaggerConverter) convertSecurity(location string, security []map[string][]string) []map[string][]string {
+	if security == nil {
+		return nil
+	}
+	converted := []map[string][]string{}
+	for i, requirement := range security {
+		convertedRequirement := map[string][]string{}
+		for name, scopes := range requirement {
+			if _, ok := c.securityDefinitions[name]; ok {
+				convertedRequirement[name] = scopes
+			}
+		}
+		if len(convertedRequirement) < len(requirement) {
+			c.warn(fmt.Sprintf("%s[%d]", location, i), "requirements of dropped security schemes")
+			if len(convertedRequirement) == 0 {
+				continue
+			}
+		}
+		converted = append(converted, convertedRequirement)
+	}
+	return converted
+}
+
+func (c *swaggerConverter) convertSecurityScheme(location string, scheme *SecuritySchemeObject) *SwaggerSecuritySchemeObject {
+	swaggerScheme := &SwaggerSecuritySchemeObject{
+		Type:        scheme.Type,
+		Description: scheme.Description,
+	}
+	switch scheme.Type {
+	case "http":
+		if !strings.EqualFold(scheme.Scheme, "basic") {
+			c.warn(location, fmt.Sprintf("http %s authentication", scheme.Scheme))
+			return nil
+		}
+		swaggerScheme.Type = "basic"
+	case "apiKey":
+		if scheme.In == "cookie" {
+			c.warn(location, "cookie api keys")
+			return nil
+		}
+		swaggerScheme.In = scheme.In
+		swaggerScheme.Name = scheme.Name
+	case "oauth2":
+		return c.convertOAuthFlows(location, swaggerScheme, scheme.OAuthFlows)
+	default:
+		c.warn(location, scheme.Type+" security schemes")
+		return nil
+	}
+	return swaggerScheme
+}
+
+// convertOAuthFlows describes an oauth2 scheme by its first flow, as a Swagger scheme has a single flow
+func (c *swaggerConverter) convertOAuthFlows(
+	location string,
+	swaggerScheme *SwaggerSecuritySchemeObject,
+	flows *SecuritySchemeOauthObject) *SwaggerSecuritySchemeObject {
+	if flows == nil {
+		return nil
+	}
+	var converted []string
+	for _, flow := range []struct {
+		name string
+		flow *SecuritySchemeOauthFlowObject
+	}{
+		{"implicit", flows.Implicit},
+		{"accessCode", flows.AuthorizationCode},
+		{"password", flows.ResourceOwnerPassword},
+		{"application", flows.ClientCredentials},
+	} {
+		if flow.flow == nil {
+			continue
+		}
+		converted = append(converted, flow.name)
+		if len(converted) > 1 {
+			continue
+		}
+		swaggerScheme.Flow = flow.name
+		swaggerScheme.AuthorizationURL = flow.flow.AuthorizationURL
+		swaggerScheme.TokenURL = flow.flow.TokenURL
+		swaggerScheme.Scopes = flow.flow.Scopes
+	}
+	if len(converted) > 1 {
+		c.warn(location, "multiple oauth2 flows")
+	}
+	if len(converted) == 0 {
+		return nil
+	}
+	return swaggerScheme
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
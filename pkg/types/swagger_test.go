@@ -0,0 +1,175 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToSwagger(t *testing.T) {
+	openAPI := &OpenAPIObject{
+		OpenAPI: OpenAPIVersion,
+		Info:    InfoObject{Title: "Pets", Version: "1.0.0"},
+		Servers: []ServerObject{
+			{URL: "https://{env}.pets.io/v1", Variables: map[string]ServerVariableObject{"env": {Default: "api"}}},
+			{URL: "https://staging.pets.io/v1"},
+		},
+		Paths: PathsObject{
+			"/pets/{id}": {
+				Get: &OperationObject{
+					OperationID: "getPet",
+					Parameters: []ParameterObject{
+						{Name: "id", In: InPath, Required: true, Schema: &SchemaObject{Ref: "#/components/schemas/ID"}},
+						{Name: "session", In: "cookie", Schema: &SchemaObject{Type: TypeString}},
+					},
+					Responses: ResponsesObject{
+						"200": {
+							Description: "A pet",
+							Content:     map[string]*MediaTypeObject{ContentTypeJSON: {Schema: SchemaObject{Ref: "#/components/schemas/Pet"}}},
+						},
+					},
+				},
+				Put: &OperationObject{
+					RequestBody: &RequestBodyObject{
+						Required: true,
+						Content:  map[string]*MediaTypeObject{ContentTypeJSON: {Schema: SchemaObject{Ref: "#/components/schemas/Pet"}}},
+					},
+					Responses: ResponsesObject{"204": {Description: "Updated"}},
+				},
+				Post: &OperationObject{
+					RequestBody: &RequestBodyObject{
+						Content: map[string]*MediaTypeObject{ContentTypeForm: {Schema: SchemaObject{
+							Type: TypeObject,
+							Properties: NewOrderedMap().
+								Set("photo", &SchemaObject{Type: TypeString, Format: "binary", Description: "Photo"}).
+								Set("caption", &SchemaObject{Type: TypeString}),
+						}}},
+					},
+					Responses: ResponsesObject{"201": {Description: "Uploaded"}},
+				},
+			},
+		},
+		Components: ComponentsObject{
+			Schemas: map[string]*SchemaObject{
+				"ID": {Type: TypeInteger, Format: "int64"},
+				"Pet": {
+					Type: TypeObject,
+					Properties: NewOrderedMap().
+						Set("id", &SchemaObject{Ref: "#/components/schemas/ID"}).
+						Set("owner", &SchemaObject{
+							Nullable: true,
+							OneOf:    []*SchemaObject{{Ref: "#/components/schemas/ID"}},
+						}),
+				},
+			},
+			SecuritySchemes: map[string]*SecuritySchemeObject{
+				"basic":  {Type: "http", Scheme: "basic"},
+				"bearer": {Type: "http", Scheme: "bearer"},
+				"oauth": {Type: "oauth2", OAuthFlows: &SecuritySchemeOauthObject{
+					ClientCredentials: &SecuritySchemeOauthFlowObject{TokenURL: "/token", Scopes: map[string]string{"read": "Read"}},
+				}},
+			},
+		},
+	}
+
+	swagger, warnings := ConvertToSwagger(openAPI)
+	b, err := json.Marshal(swagger)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"swagger": "2.0",
+		"info": {"title": "Pets", "version": "1.0.0"},
+		"host": "api.pets.io",
+		"basePath": "/v1",
+		"schemes": ["https"],
+		"paths": {
+			"/pets/{id}": {
+				"get": {
+					"operationId": "getPet",
+					"produces": ["application/json"],
+					"parameters": [{"name": "id", "in": "path", "required": true, "type": "integer", "format": "int64"}],
+					"responses": {"200": {"description": "A pet", "schema": {"$ref": "#/definitions/Pet"}}}
+				},
+				"put": {
+					"consumes": ["application/json"],
+					"parameters": [{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}],
+					"responses": {"204": {"description": "Updated"}}
+				},
+				"post": {
+					"consumes": ["multipart/form-data"],
+					"parameters": [
+						{"name": "photo", "in": "formData", "description": "Photo", "type": "file"},
+						{"name": "caption", "in": "formData", "type": "string"}
+					],
+					"responses": {"201": {"description": "Uploaded"}}
+				}
+			}
+		},
+		"definitions": {
+			"ID": {"type": "integer", "format": "int64"},
+			"Pet": {"type": "object", "properties": {"id": {"$ref": "#/definitions/ID"}, "owner": {}}}
+		},
+		"securityDefinitions": {
+			"basic": {"type": "basic"},
+			"oauth": {"type": "oauth2", "flow": "application", "tokenUrl": "/token", "scopes": {"read": "Read"}}
+		}
+	}`, string(b))
+	assert.Equal(t, []ConversionWarning{
+		{Location: "servers", Construct: "multiple servers"},
+		{Location: "paths./pets/{id}.get.parameters[1]", Construct: "cookie parameters"},
+		{Location: "definitions.Pet", Construct: "oneOf"},
+		{Location: "securityDefinitions.bearer", Construct: "http bearer authentication"},
+	}, warnings)
+
+	// the OpenAPI document is left as is
+	owner, _ := openAPI.Components.Schemas["Pet"].Properties.Get("owner")
+	assert.Len(t, owner.(*SchemaObject).OneOf, 1)
+	assert.Equal(t, "#/components/schemas/Pet", openAPI.Paths["/pets/{id}"].Get.Responses["200"].Content[ContentTypeJSON].Schema.Ref)
+}
+
+func TestConvertToSwaggerSecurity(t *testing.T) {
+	openAPI := &OpenAPIObject{
+		OpenAPI:  OpenAPIVersion,
+		Info:     InfoObject{Title: "Pets", Version: "1.0.0"},
+		Security: []map[string][]string{{"bearer": {}}, {"apiKey": {}}},
+		Paths: PathsObject{
+			"/pets": {
+				Get: &OperationObject{
+					Security:  []map[string][]string{{"bearer": {}, "apiKey": {}}, {}},
+					Responses: ResponsesObject{"200": {Description: "Pets"}},
+				},
+			},
+		},
+		Components: ComponentsObject{
+			SecuritySchemes: map[string]*SecuritySchemeObject{
+				"apiKey": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+				"bearer": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+
+	swagger, warnings := ConvertToSwagger(openAPI)
+	b, err := json.Marshal(swagger)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"swagger": "2.0",
+		"info": {"title": "Pets", "version": "1.0.0"},
+		"security": [{"apiKey": []}],
+		"paths": {
+			"/pets": {
+				"get": {
+					"security": [{"apiKey": []}, {}],
+					"responses": {"200": {"description": "Pets"}}
+				}
+			}
+		},
+		"securityDefinitions": {
+			"apiKey": {"type": "apiKey", "in": "header", "name": "X-API-Key"}
+		}
+	}`, string(b))
+	assert.Equal(t, []ConversionWarning{
+		{Location: "security[0]", Construct: "requirements of dropped security schemes"},
+		{Location: "paths./pets.get.security[0]", Construct: "requirements of dropped security schemes"},
+		{Location: "securityDefinitions.bearer", Construct: "http bearer authentication"},
+	}, warnings)
+}
@@ -0,0 +1,495 @@
+package types
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+//go:embed schemas/openapi-3.0.json
+var openAPI30MetaSchema string
+
+//go:embed schemas/openapi-3.1.json
+var openAPI31MetaSchema string
+
+//go:embed schemas/swagger-2.0.json
+var swagger20MetaSchema string
+
+// draft04MetaSchema is referenced by the Swagger 2.0 meta-schema
+//
+//go:embed schemas/draft-04.json
+var draft04MetaSchema string
+
+const (
+	openAPI30MetaSchemaURL = "https://spec.openapis.org/oas/3.0/schema/2021-09-28"
+	openAPI31MetaSchemaURL = "https://spec.openapis.org/oas/3.1/schema/2022-10-07"
+	swagger20MetaSchemaURL = "http://swagger.io/v2/schema.json"
+	draft04MetaSchemaURL   = "http://json-schema.org/draft-04/schema"
+)
+
+var (
+	compileMetaSchemas sync.Once
+	// metaSchemas are keyed by the version of the documents they validate
+	metaSchemas map[string]*jsonschema.Schema
+)
+
+// Violation of the OpenAPI specification at Pointer, the json pointer of the value that violates it
+type Violation struct {
+	Pointer string
+	Message string
+}
+
+// String renders the violation as pointer: message
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Pointer, v.Message)
+}
+
+// ValidateOpenAPI validates a document built as OpenAPI 3.0 against the OpenAPI 3.0 meta-schema, embedded in the
+// binary, and checks what the meta-schema cannot, see ValidateDocument.
+//
+// Webhooks, info.summary, license.identifier and const are not part of 3.0, they are left out of the meta-schema
+// validation of documents that are converted to 3.1.
+func ValidateOpenAPI(o *OpenAPIObject) ([]Violation, error) {
+	document, err := decodeJSON(o)
+	if err != nil {
+		return nil, err
+	}
+	violations := documentViolations(document)
+	schemaViolations, err := validateMetaSchema(SpecVersion30, withoutOpenAPI31(document))
+	if err != nil {
+		return nil, err
+	}
+	return sortViolations(append(violations, schemaViolations...)), nil
+}
+
+// ValidateDocument validates a decoded document against the meta-schema of its version, OpenAPI 3.1 if its openapi
+// is 3.1.x, Swagger 2.0 if its swagger is 2.0 and OpenAPI 3.0 otherwise, all of which are embedded in the binary. It
+// then checks what the meta-schema cannot: that every local $ref resolves, that the parameters of a path template
+// are declared, and that every response is described. Violations are sorted by their pointer.
+func ValidateDocument(document interface{}) ([]Violation, error) {
+	document, err := decodeJSON(document)
+	if err != nil {
+		return nil, err
+	}
+	violations := documentViolations(document)
+	schemaViolations, err := validateMetaSchema(DocumentSpecVersion(document), document)
+	if err != nil {
+		return nil, err
+	}
+	return sortViolations(append(violations, schemaViolations...)), nil
+}
+
+// Versions of the specification a document is validated against
+const (
+	SpecVersion20 = "2.0"
+	SpecVersion30 = "3.0"
+	SpecVersion31 = "3.1"
+)
+
+// DocumentSpecVersion returns the version of the specification of a decoded document, see ValidateDocument
+func DocumentSpecVersion(document interface{}) string {
+	root, _ := document.(map[string]interface{})
+	if swagger, ok := root["swagger"].(string); ok && swagger == SwaggerVersion {
+		return SpecVersion20
+	}
+	if openAPI, ok := root["openapi"].(string); ok && strings.HasPrefix(openAPI, SpecVersion31+".") {
+		return SpecVersion31
+	}
+	return SpecVersion30
+}
+
+// validateMetaSchema validates a document against the meta-schema of version
+func validateMetaSchema(version string, document interface{}) ([]Violation, error) {
+	compileMetaSchemas.Do(func() {
+		compiler := jsonschema.NewCompiler()
+		for url, schema := range map[string]string{
+			draft04MetaSchemaURL:   draft04MetaSchema,
+			swagger20MetaSchemaURL: swagger20MetaSchema,
+		} {
+			if err := compiler.AddResource(url, strings.NewReader(schema)); err != nil {
+				panic(err)
+			}
+		}
+		metaSchemas = map[string]*jsonschema.Schema{
+			SpecVersion20: compiler.MustCompile(swagger20MetaSchemaURL),
+			SpecVersion30: jsonschema.MustCompileString(openAPI30MetaSchemaURL, openAPI30MetaSchema),
+			SpecVersion31: jsonschema.MustCompileString(openAPI31MetaSchemaURL, openAPI31MetaSchema),
+		}
+	})
+	err := metaSchemas[version].Validate(document)
+	if err == nil {
+		return nil, nil
+	}
+	validationError, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, err
+	}
+	return schemaViolations(validationError), nil
+}
+
+// documentViolations lists the violations of a decoded document the meta-schemas cannot check
+func documentViolations(document interface{}) []Violation {
+	root, _ := document.(map[string]interface{})
+	var violations []Violation
+	violations = append(violations, refViolations(document, "", document)...)
+	violations = append(violations, pathViolations(root)...)
+	violations = append(violations, responseViolations(root)...)
+	return violations
+}
+
+func sortViolations(violations []Violation) []Violation {
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Pointer < violations[j].Pointer
+	})
+	return violations
+}
+
+// decodeJSON round trips a value through json, so that it is made of the values the meta-schemas validate
+func decodeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// schemaViolations flattens a validation error into the violations it was caused by, once per pointer and message
+func schemaViolations(validationError *jsonschema.ValidationError) []Violation {
+	var violations []Violation
+	seen := map[Violation]bool{}
+	for _, violation := range causeViolations(validationError) {
+		if !seen[violation] {
+			seen[violation] = true
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+func causeViolations(e *jsonschema.ValidationError) []Violation {
+	if len(e.Causes) == 0 {
+		pointer, err := url.PathUnescape(e.InstanceLocation)
+		if err != nil {
+			pointer = e.InstanceLocation
+		}
+		return []Violation{{Pointer: pointer, Message: e.Message}}
+	}
+
+	causes := e.Causes
+	alternatives := strings.HasSuffix(e.KeywordLocation, "/oneOf") || strings.HasSuffix(e.KeywordLocation, "/anyOf")
+	if alternatives {
+		causes = withoutReferences(causes)
+	}
+	var violations []Violation
+	for _, cause := range causes {
+		violations = append(violations, causeViolations(cause)...)
+	}
+	if alternatives && len(causes) > 1 {
+		return alternativeViolations(violations)
+	}
+	return violations
+}
+
+// withoutReferences drops the alternatives of a schema that failed because a value is not a reference object, as
+// values are only meant to be one if they have a $ref
+func withoutReferences(causes []*jsonschema.ValidationError) []*jsonschema.ValidationError {
+	var filtered []*jsonschema.ValidationError
+	for _, cause := range causes {
+		if !strings.HasSuffix(cause.Message, "'/definitions/Reference'") {
+			filtered = append(filtered, cause)
+		}
+	}
+	if len(filtered) == 0 {
+		return causes
+	}
+	return filtered
+}
+
+// alternativeViolations merges the violations of a value that matches none of the alternatives of a schema, keeping
+// those of the values nested the deepest, where the alternatives differ
+func alternativeViolations(violations []Violation) []Violation {
+	depth := 0
+	for _, violation := range violations {
+		if d := strings.Count(violation.Pointer, "/"); d > depth {
+			depth = d
+		}
+	}
+	var merged []Violation
+	index := map[string]int{}
+	for _, violation := range violations {
+		if strings.Count(violation.Pointer, "/") != depth {
+			continue
+		}
+		i, ok := index[violation.Pointer]
+		if !ok {
+			index[violation.Pointer] = len(merged)
+			merged = append(merged, violation)
+			continue
+		}
+		if !strings.Contains(merged[i].Message, violation.Message) {
+			merged[i].Message += " or " + violation.Message
+		}
+	}
+	return merged
+}
+
+// refViolations lists the local $refs nested in value that do not resolve in document
+func refViolations(document interface{}, pointer string, value interface{}) []Violation {
+	var violations []Violation
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+			if _, ok := resolvePointer(document, strings.TrimPrefix(ref, "#")); !ok {
+				violations = append(violations, Violation{Pointer: pointer, Message: fmt.Sprintf("$ref %s does not resolve", ref)})
+			}
+		}
+		for _, key := range sortedKeys(v) {
+			violations = append(violations, refViolations(document, pointer+"/"+escapePointer(key), v[key])...)
+		}
+	case []interface{}:
+		for i := range v {
+			violations = append(violations, refViolations(document, pointer+"/"+strconv.Itoa(i), v[i])...)
+		}
+	}
+	return violations
+}
+
+// resolvePointer returns the value at a json pointer of document
+func resolvePointer(document interface{}, pointer string) (interface{}, bool) {
+	if pointer == "" {
+		return document, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	value := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+var pathTemplateParameter = regexp.MustCompile(`{([^}]+)}`)
+
+// pathViolations lists the parameters of path templates that operations do not declare, and the path parameters
+// path items and operations declare that are not in their path template. Parameters are followed through their
+// $refs, and operations inherit the parameters of their path item.
+func pathViolations(document map[string]interface{}) []Violation {
+	var violations []Violation
+	paths, _ := document["paths"].(map[string]interface{})
+	for _, path := range sortedKeys(paths) {
+		templated := map[string]bool{}
+		for _, match := range pathTemplateParameter.FindAllStringSubmatch(path, -1) {
+			templated[match[1]] = true
+		}
+		pathItem, _ := paths[path].(map[string]interface{})
+		inherited, pathItemViolations := pathParameterViolations(document, JSONPointer("paths", path), pathItem, templated)
+		violations = append(violations, pathItemViolations...)
+		for _, method := range operationMethods {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			pointer := JSONPointer("paths", path, method)
+			declared, operationViolations := pathParameterViolations(document, pointer, operation, templated)
+			violations = append(violations, operationViolations...)
+			for _, name := range sortedKeys(templated) {
+				if !declared[name] && !inherited[name] {
+					violations = append(violations, Violation{
+						Pointer: pointer,
+						Message: fmt.Sprintf("path parameter %s of the path template is not declared", name),
+					})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// pathParameterViolations returns the path parameters of the path item or operation at pointer, and lists those that
+// are not in the path template
+func pathParameterViolations(document interface{}, pointer string, object map[string]interface{}, templated map[string]bool) (map[string]bool, []Violation) {
+	var violations []Violation
+	declared := map[string]bool{}
+	parameters, _ := object["parameters"].([]interface{})
+	for i, parameter := range parameters {
+		parameter := resolveReference(document, parameter)
+		name, _ := parameter["name"].(string)
+		if parameter["in"] != InPath {
+			continue
+		}
+		declared[name] = true
+		if !templated[name] {
+			violations = append(violations, Violation{
+				Pointer: pointer + "/parameters/" + strconv.Itoa(i),
+				Message: fmt.Sprintf("path parameter %s is not in the path template", name),
+			})
+		}
+	}
+	return declared, violations
+}
+
+// resolveReference follows the local $refs of an object to the object they reference, or returns nil if they do
+// not resolve
+func resolveReference(document interface{}, value interface{}) map[string]interface{} {
+	seen := map[string]bool{}
+	for {
+		object, _ := value.(map[string]interface{})
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object
+		}
+		if !strings.HasPrefix(ref, "#") || seen[ref] {
+			return nil
+		}
+		seen[ref] = true
+		if value, ok = resolvePointer(document, strings.TrimPrefix(ref, "#")); !ok {
+			return nil
+		}
+	}
+}
+
+// responseViolations lists the responses without a description, which the meta-schemas require but cannot require
+// to be non-empty
+func responseViolations(document map[string]interface{}) []Violation {
+	var violations []Violation
+	for _, section := range []string{"paths", "webhooks"} {
+		paths, _ := document[section].(map[string]interface{})
+		for _, path := range sortedKeys(paths) {
+			pathItem, _ := paths[path].(map[string]interface{})
+			for _, method := range operationMethods {
+				operation, _ := pathItem[method].(map[string]interface{})
+				responses, _ := operation["responses"].(map[string]interface{})
+				for _, status := range sortedKeys(responses) {
+					response, _ := responses[status].(map[string]interface{})
+					if _, ok := response["$ref"]; !ok && response["description"] == "" {
+						violations = append(violations, Violation{
+							Pointer: JSONPointer(section, path, method, "responses", status),
+							Message: "response has no description",
+						})
+					}
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// withoutOpenAPI31 removes what OpenAPI 3.1 adds to a decoded document built as 3.0
+func withoutOpenAPI31(document interface{}) interface{} {
+	root, ok := document.(map[string]interface{})
+	if !ok {
+		return document
+	}
+	delete(root, "webhooks")
+	if info, ok := root["info"].(map[string]interface{}); ok {
+		delete(info, "summary")
+		if license, ok := info["license"].(map[string]interface{}); ok {
+			delete(license, "identifier")
+		}
+	}
+	walkDecodedSchemas(root, func(schema map[string]interface{}) {
+		delete(schema, "const")
+	})
+	return root
+}
+
+// walkDecodedSchemas calls fn for every schema of a decoded document, including the schemas nested in other schemas
+func walkDecodedSchemas(document map[string]interface{}, fn func(schema map[string]interface{})) {
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		schema, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fn(schema)
+		for _, key := range []string{"items", "not", "additionalProperties"} {
+			walk(schema[key])
+		}
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			for _, property := range properties {
+				walk(property)
+			}
+		}
+		for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+			if schemas, ok := schema[key].([]interface{}); ok {
+				for _, nested := range schemas {
+					walk(nested)
+				}
+			}
+		}
+	}
+	walkContent := func(value interface{}) {
+		content, _ := value.(map[string]interface{})
+		for _, mediaType := range content {
+			if mediaType, ok := mediaType.(map[string]interface{}); ok {
+				walk(mediaType["schema"])
+			}
+		}
+	}
+
+	if components, ok := document["components"].(map[string]interface{}); ok {
+		if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+			for _, schema := range schemas {
+				walk(schema)
+			}
+		}
+	}
+	paths, _ := document["paths"].(map[string]interface{})
+	for _, pathItem := range paths {
+		pathItem, _ := pathItem.(map[string]interface{})
+		for _, method := range operationMethods {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			parameters, _ := operation["parameters"].([]interface{})
+			for _, parameter := range parameters {
+				if parameter, ok := parameter.(map[string]interface{}); ok {
+					walk(parameter["schema"])
+				}
+			}
+			if requestBody, ok := operation["requestBody"].(map[string]interface{}); ok {
+				walkContent(requestBody["content"])
+			}
+			responses, _ := operation["responses"].(map[string]interface{})
+			for _, response := range responses {
+				response, _ := response.(map[string]interface{})
+				headers, _ := response["headers"].(map[string]interface{})
+				for _, header := range headers {
+					if header, ok := header.(map[string]interface{}); ok {
+						walk(header["schema"])
+					}
+				}
+				walkContent(response["content"])
+			}
+		}
+	}
+}
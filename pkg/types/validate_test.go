@@ -0,0 +1,223 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOpenAPI(t *testing.T) {
+	document := func(mutate func(o *OpenAPIObject)) *OpenAPIObject {
+		o := &OpenAPIObject{
+			OpenAPI: OpenAPIVersion,
+			Info:    InfoObject{Title: "Pets", Version: "1.0.0"},
+			Paths: PathsObject{
+				"/pets/{id}": {
+					Get: &OperationObject{
+						Parameters: []ParameterObject{{Name: "id", In: InPath, Required: true, Schema: &SchemaObject{Type: TypeString}}},
+						Responses: ResponsesObject{"200": {
+							Description: "A pet",
+							Content:     map[string]*MediaTypeObject{ContentTypeJSON: {Schema: SchemaObject{Ref: "#/components/schemas/Pet"}}},
+						}},
+					},
+				},
+			},
+			Components: ComponentsObject{Schemas: map[string]*SchemaObject{
+				"Pet": {Type: TypeObject, Properties: NewOrderedMap().Set("name", &SchemaObject{Type: TypeString})},
+			}},
+		}
+		if mutate != nil {
+			mutate(o)
+		}
+		return o
+	}
+
+	tests := map[string]struct {
+		document       *OpenAPIObject
+		wantViolations []Violation
+	}{
+		"valid": {
+			document: document(nil),
+		},
+		"openapi 3.1 additions are not validated": {
+			document: document(func(o *OpenAPIObject) {
+				o.Info.Summary = "Pet store"
+				o.Webhooks = PathsObject{"newPet": {Post: &OperationObject{Responses: ResponsesObject{"200": {Description: "OK"}}}}}
+				o.Components.Schemas["Pet"].Properties.Set("kind", &SchemaObject{Type: TypeString, Const: "cat"})
+			}),
+		},
+		"unresolved ref": {
+			document: document(func(o *OpenAPIObject) {
+				delete(o.Components.Schemas, "Pet")
+			}),
+			wantViolations: []Violation{
+				{Pointer: "/paths/~1pets~1{id}/get/responses/200/content/application~1json/schema", Message: "$ref #/components/schemas/Pet does not resolve"},
+			},
+		},
+		"path parameters": {
+			document: document(func(o *OpenAPIObject) {
+				o.Paths["/owners/{owner}"] = &PathItemObject{Get: &OperationObject{
+					Parameters: []ParameterObject{{Name: "id", In: InPath, Required: true, Schema: &SchemaObject{Type: TypeString}}},
+					Responses:  ResponsesObject{"200": {Description: "An owner"}},
+				}}
+			}),
+			wantViolations: []Violation{
+				{Pointer: "/paths/~1owners~1{owner}/get", Message: "path parameter owner of the path template is not declared"},
+				{Pointer: "/paths/~1owners~1{owner}/get/parameters/0", Message: "path parameter id is not in the path template"},
+			},
+		},
+		"responses": {
+			document: document(func(o *OpenAPIObject) {
+				o.Paths["/pets/{id}"].Get.Responses["200"].Description = ""
+				o.Paths["/pets/{id}"].Delete = &OperationObject{
+					Parameters: []ParameterObject{{Name: "id", In: InPath, Required: true, Schema: &SchemaObject{Type: TypeString}}},
+					Responses:  ResponsesObject{},
+				}
+			}),
+			wantViolations: []Violation{
+				{Pointer: "/paths/~1pets~1{id}/delete/responses", Message: "minimum 1 properties allowed, but found 0 properties"},
+				{Pointer: "/paths/~1pets~1{id}/get/responses/200", Message: "response has no description"},
+			},
+		},
+		"meta-schema": {
+			document: document(func(o *OpenAPIObject) {
+				o.Paths["/pets/{id}"].Get.Parameters = append(o.Paths["/pets/{id}"].Get.Parameters, ParameterObject{
+					Name: "limit", In: "body", Schema: &SchemaObject{Type: TypeInteger},
+				})
+			}),
+			wantViolations: []Violation{
+				{Pointer: "/paths/~1pets~1{id}/get/parameters/1/in", Message: `value must be "path" or value must be "query" or value must be "header" or value must be "cookie"`},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			violations, err := ValidateOpenAPI(tc.document)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantViolations, violations)
+		})
+	}
+}
+
+func TestValidateDocument(t *testing.T) {
+	tests := map[string]struct {
+		document       string
+		wantVersion    string
+		wantViolations []Violation
+	}{
+		"openapi 3.0 components": {
+			document: `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /owners/{owner}/pets/{id}:
+    parameters:
+      - $ref: '#/components/parameters/owner'
+    get:
+      parameters:
+        - $ref: '#/components/parameters/id'
+      responses:
+        '200': {$ref: '#/components/responses/pet'}
+components:
+  parameters:
+    owner: {name: owner, in: path, required: true, schema: {type: string}}
+    id: {name: id, in: path, required: true, schema: {type: string}}
+  responses:
+    pet: {description: A pet}
+`,
+			wantVersion: SpecVersion30,
+		},
+		"openapi 3.0 unresolved parameter": {
+			document: `
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/id'
+      responses:
+        '200': {description: A pet}
+`,
+			wantVersion: SpecVersion30,
+			wantViolations: []Violation{
+				{Pointer: "/paths/~1pets~1{id}/get", Message: "path parameter id of the path template is not declared"},
+				{Pointer: "/paths/~1pets~1{id}/get/parameters/0", Message: "$ref #/components/parameters/id does not resolve"},
+			},
+		},
+		"openapi 3.1": {
+			document: `
+openapi: 3.1.0
+info: {title: Pets, summary: Pet store, version: 1.0.0, license: {name: MIT, identifier: MIT}}
+paths: {}
+webhooks:
+  newPet:
+    post:
+      responses:
+        '200': {description: OK}
+components:
+  schemas:
+    Cat:
+      type: object
+      properties:
+        kind: {const: cat}
+`,
+			wantVersion: SpecVersion31,
+		},
+		"openapi 3.1 violations": {
+			document: `
+openapi: 3.1.0
+info: {title: Pets, version: 1.0.0}
+webhooks:
+  newPet:
+    post:
+      responses:
+        '200': {description: ''}
+`,
+			wantVersion: SpecVersion31,
+			wantViolations: []Violation{
+				{Pointer: "/webhooks/newPet/post/responses/200", Message: "response has no description"},
+			},
+		},
+		"swagger 2.0": {
+			document: `
+swagger: '2.0'
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - {name: id, in: path, required: true, type: string}
+      responses:
+        '200': {description: A pet, schema: {$ref: '#/definitions/Pet'}}
+definitions:
+  Pet: {type: object, properties: {name: {type: string}}}
+`,
+			wantVersion: SpecVersion20,
+		},
+		"swagger 2.0 violations": {
+			document: `
+swagger: '2.0'
+info: {title: Pets, version: 1.0.0}
+basePath: api
+paths: {}
+`,
+			wantVersion: SpecVersion20,
+			wantViolations: []Violation{
+				{Pointer: "/basePath", Message: "does not match pattern '^/'"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			document, err := DecodeDocument([]byte(tc.document))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantVersion, DocumentSpecVersion(document))
+			violations, err := ValidateDocument(document)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantViolations, violations)
+		})
+	}
+}
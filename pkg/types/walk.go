@@ -0,0 +1,77 @@
+package types
+
+import "sort"
+
+// WalkSchemas calls fn for every schema of the document, including the schemas nested in other schemas.
+// Schemas are visited once, even when they are shared.
+func (o *OpenAPIObject) WalkSchemas(fn func(schema *SchemaObject)) {
+	visited := map[*SchemaObject]bool{}
+	walk := func(schema *SchemaObject) {
+		walkSchema(schema, visited, fn)
+	}
+
+	for _, name := range sortedKeys(o.Components.Schemas) {
+		walk(o.Components.Schemas[name])
+	}
+	for _, paths := range []PathsObject{o.Paths, o.Webhooks} {
+		for _, path := range sortedKeys(paths) {
+			for _, operation := range paths[path].Operations() {
+				walkOperation(operation, walk)
+			}
+		}
+	}
+}
+
+func walkOperation(operation *OperationObject, walk func(schema *SchemaObject)) {
+	for i := range operation.Parameters {
+		walk(operation.Parameters[i].Schema)
+	}
+	if operation.RequestBody != nil {
+		for _, contentType := range sortedKeys(operation.RequestBody.Content) {
+			walk(&operation.RequestBody.Content[contentType].Schema)
+		}
+	}
+	for _, status := range sortedKeys(operation.Responses) {
+		response := operation.Responses[status]
+		for _, name := range sortedKeys(response.Headers) {
+			walk(response.Headers[name].Schema)
+		}
+		for _, contentType := range sortedKeys(response.Content) {
+			walk(&response.Content[contentType].Schema)
+		}
+	}
+}
+
+func walkSchema(schema *SchemaObject, visited map[*SchemaObject]bool, fn func(schema *SchemaObject)) {
+	if schema == nil || visited[schema] {
+		return
+	}
+	visited[schema] = true
+	fn(schema)
+
+	walkSchema(schema.Items, visited, fn)
+	walkSchema(schema.Not, visited, fn)
+	walkSchema(schema.AdditionalProperties, visited, fn)
+	if schema.Properties != nil {
+		for _, key := range schema.Properties.Keys() {
+			property, _ := schema.Properties.Get(key)
+			if propertySchema, ok := property.(*SchemaObject); ok {
+				walkSchema(propertySchema, visited, fn)
+			}
+		}
+	}
+	for _, composed := range [][]*SchemaObject{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, composedSchema := range composed {
+			walkSchema(composedSchema, visited, fn)
+		}
+	}
+}
+
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,15 +1,19 @@
 package integration_handler
 
+import (
+	"github.com/deanstalker/goas/test/integration"
+)
+
 // @Title List all pets
 // @ID listPets
 // @Tag pets
 // @Param limit query int false "How many items to return at one time (max 100)"
-// @Success 200 object Pets "A paged array of pets"
+// @Success 200 object integration.Pets "A paged array of pets"
 // @Header 200 x-next string string "A link to the next page of responses"
 // @Failure default object integration.Error "unexpected error"
 // @Route /pets [get]
-func listPets() {
-
+func listPets() (integration.Pets, *integration.Error) {
+	return nil, nil
 }
 
 // @Title Create a pet
@@ -18,16 +22,16 @@ func listPets() {
 // @Success 201 object "Null response"
 // @Failure default object integration.Error "unexpected error"
 // @Route /pets [post]
-func createPets() {
-
+func createPets() *integration.Error {
+	return nil
 }
 
 // @Title Info for a specific pet
 // @ID showPetById
 // @Tag pets
 // @Param petId path string true "The id of the pet to retrieve"
-// @Success 200 object Pet "Expected response to a valid request"
+// @Success 200 object integration.Pet "Expected response to a valid request"
 // @Failure default object integration.Error "unexpected error"
-func showPetById() {
-
+func showPetById() (*integration.Pet, *integration.Error) {
+	return nil, nil
 }
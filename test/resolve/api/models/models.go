@@ -0,0 +1,6 @@
+package models
+
+// Error returned by the public api
+type Error struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,23 @@
+package resolve
+
+import (
+	"github.com/deanstalker/goas/test/resolve/internal/models"
+)
+
+// @Title Resolve a type declared in the handler
+// @Param request body Request true "Request"
+// @Success 200 object Response "Response"
+// @Failure 500 object models.Error "Internal error"
+// @Route /resolve [post]
+func resolve() {
+	type Request struct {
+		Name string `json:"name"`
+		Meta struct {
+			Trace string `json:"trace"`
+		} `json:"meta"`
+	}
+	type Response struct {
+		Error models.Error `json:"error"`
+	}
+	_, _ = Request{}, Response{}
+}
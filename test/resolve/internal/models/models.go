@@ -0,0 +1,7 @@
+package models
+
+// Error returned by internal services
+type Error struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,20 @@
+package unit
+
+// Page of results described with anonymous structs
+type Page struct {
+	Meta struct {
+		Page  int `json:"page" minimum:"1"`
+		Total int `json:"total"`
+	} `json:"meta" description:"Paging metadata"`
+	Items []struct {
+		ID string `json:"id,required"`
+	} `json:"items" maxItems:"100"`
+	Labels map[string]*struct {
+		Color string `json:"color"`
+	} `json:"labels"`
+}
+
+// Rows of anonymous structs
+type Rows []struct {
+	Value string `json:"value"`
+}
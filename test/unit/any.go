@@ -0,0 +1,7 @@
+package unit
+
+// Envelope holds values of any type
+type Envelope struct {
+	Value  any   `json:"value"`
+	Values []any `json:"values"`
+}
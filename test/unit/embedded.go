@@ -0,0 +1,38 @@
+package unit
+
+// User account holder
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name,required"`
+}
+
+// Audit of who last changed a record
+type Audit struct {
+	Name      string `json:"name"`
+	UpdatedBy string `json:"updated_by"`
+}
+
+// Admin promotes the fields of the user it embeds
+type Admin struct {
+	User
+	Role string `json:"role"`
+}
+
+// Moderator shadows the name of the user it embeds
+type Moderator struct {
+	*User
+	Name string `json:"nickname"`
+	Nick string `json:"name"`
+}
+
+// Editor embeds two structs with a conflicting name, so neither is serialized
+type Editor struct {
+	User
+	*Audit
+}
+
+// Owner names the embedded user with a json tag and ignores the embedded audit
+type Owner struct {
+	User  `json:"user"`
+	Audit `json:"-"`
+}
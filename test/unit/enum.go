@@ -0,0 +1,25 @@
+package unit
+
+// Status of an account
+type Status string
+
+const (
+	// StatusActive accounts can sign in
+	StatusActive   Status = "active"
+	StatusPending  Status = "pending" // StatusPending accounts are awaiting approval
+	StatusDisabled Status = "disabled"
+)
+
+// Priority of a ticket
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityHigh
+)
+
+// Ticket with named basic types
+type Ticket struct {
+	Status   Status   `json:"status"`
+	Priority Priority `json:"priority"`
+}
@@ -0,0 +1,6 @@
+package unit
+
+// Location shares its name with time.Location, which is declared in a dependency of the module
+type Location struct {
+	Name string `json:"name"`
+}
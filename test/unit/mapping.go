@@ -0,0 +1,16 @@
+package unit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Schedule with types documented by type mappings
+type Schedule struct {
+	Every    time.Duration            `json:"every"`
+	Starts   time.Time                `json:"starts"`
+	Payload  json.RawMessage          `json:"payload"`
+	Price    Money                    `json:"price"`
+	Skipped  []time.Time              `json:"skipped"`
+	Timeouts map[string]time.Duration `json:"timeouts"`
+}
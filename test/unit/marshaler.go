@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Money is marshalled as a decimal string
+type Money struct {
+	units int64
+	nanos int32
+}
+
+// MarshalJSON encodes the amount as a decimal string
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(m.units, 10))
+}
+
+// Coordinates are marshalled as a pair of numbers
+// @Schema array
+type Coordinates struct {
+	lat, lng float64
+}
+
+// MarshalJSON encodes the coordinates as [lat, lng]
+func (c *Coordinates) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]float64{c.lat, c.lng})
+}
+
+// Level is marshalled by name
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+)
+
+// MarshalText encodes the level by name
+func (l Level) MarshalText() ([]byte, error) {
+	if l == LevelDebug {
+		return []byte("debug"), nil
+	}
+	return []byte("info"), nil
+}
+
+// Timeout is marshalled as a number of seconds
+// @Schema integer int64
+type Timeout time.Duration
+
+// MarshalJSON encodes the timeout in seconds
+func (t Timeout) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(time.Duration(t) / time.Second))
+}
+
+// Stamped embeds time.Time, so it is marshalled as a time
+type Stamped struct {
+	time.Time
+}
+
+// Invoice with custom marshalled fields
+type Invoice struct {
+	Total   Money        `json:"total"`
+	Where   *Coordinates `json:"where"`
+	Level   Level        `json:"level"`
+	Timeout Timeout      `json:"timeout"`
+}
@@ -0,0 +1,11 @@
+package unit
+
+// Profile with fields that may be marshalled as null
+type Profile struct {
+	Name     string            `json:"name"`
+	Nickname *string           `json:"nickname"`
+	Aliases  []string          `json:"aliases"`
+	Labels   map[string]string `json:"labels"`
+	Manager  *Blob             `json:"manager"`
+	Codes    [2]int32          `json:"codes"`
+}
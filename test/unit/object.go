@@ -4,8 +4,17 @@ package unit
 // @Title Enumerator Properties
 // @Description test to ensure enums are handled
 type EnumProperties struct {
-	Status    string `json:"status" enum:"active,pending,disabled"`
-	ErrorCode int64  `json:"error_code" enum:"400,404,500"`
+	Status    string  `json:"status" enum:"active,pending,disabled"`
+	ErrorCode int64   `json:"error_code" enum:"400,404,500"`
+	Ratio     float64 `json:"ratio" enum:"0.5,1.5"`
+	Enabled   bool    `json:"enabled" enum:"true"`
+	Label     string  `json:"label" enum:"\"red, green\",blue"`
+	Codes     []int   `json:"codes" enum:"1,2"`
+}
+
+// InvalidEnumProperties has an enum value that is not an integer
+type InvalidEnumProperties struct {
+	ErrorCode int64 `json:"error_code" enum:"400,not-found"`
 }
 
 // LimitedObjectProperties properties with applied limits
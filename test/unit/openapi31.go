@@ -0,0 +1,8 @@
+package unit
+
+// Subscription with keywords documented differently by OpenAPI 3.1
+type Subscription struct {
+	Kind   string `json:"kind" const:"subscription"`
+	Seats  int64  `json:"seats" minimum:"0" exclusiveMinimum:"true" maximum:"100" example:"5"`
+	Status Status `json:"status" example:"active"`
+}
@@ -0,0 +1,17 @@
+package unit
+
+// Blob of base64 encoded bytes
+type Blob []byte
+
+// Widths of basic types
+type Widths struct {
+	Small    int8    `json:"small"`
+	Rune     rune    `json:"rune"`
+	Count    uint16  `json:"count"`
+	Total    uint64  `json:"total" minimum:"1"`
+	Ratio    float32 `json:"ratio"`
+	Data     []byte  `json:"data"`
+	Blob     Blob    `json:"blob"`
+	Ids      []int32 `json:"ids"`
+	Checksum *[]byte `json:"checksum"`
+}